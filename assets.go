@@ -34,7 +34,10 @@ type Token struct {
 	TokenName    string        `json:"tokenName"`
 	TokenSymbol  string        `json:"tokenSymbol"`
 	Admin        string        `json:"admin"`
+	PendingAdmin string        `json:"pendingAdmin,omitempty"`
+	Paused       bool          `json:"paused,omitempty"`
 	TotalSupply  string        `json:"totalSupply"`
+	MaxSupply    string        `json:"maxSupply,omitempty"`
 	Decimals     uint64        `json:"decimals"`
 	MetaData     TokenMetaData `json:"tokenMetaData"`
 	TokenAddress string        `json:"tokenAddress"`
@@ -48,13 +51,24 @@ type Response struct {
 	Data    interface{} `json:"data"`
 }
 
-// LockedTokens locked tokens
+// LockedTokens locked tokens, BUSY vestings are minted when unlocked while BUSY20 vestings are funded
+// up front and held by escrow address
 type LockedTokens struct {
 	DocType        string `json:"docType"`
+	ID             string `json:"id,omitempty"`
+	Address        string `json:"address,omitempty"`
+	Grantor        string `json:"grantor,omitempty"`
+	Token          string `json:"token,omitempty"`
+	Escrow         string `json:"escrow,omitempty"`
 	TotalAmount    string `json:"totalAmount"`
 	ReleasedAmount string `json:"releasedAmount"`
 	StartedAt      uint64 `json:"startedAt"`
+	CliffAt        uint64 `json:"cliffAt,omitempty"`
 	ReleaseAt      uint64 `json:"releaseAt"`
+	Revocable      bool   `json:"revocable,omitempty"`
+	Revoked        bool   `json:"revoked,omitempty"`
+	RevokedAmount  string `json:"revokedAmount,omitempty"`
+	RevokedAt      uint64 `json:"revokedAt,omitempty"`
 }
 
 // Pool represents the data of overall Governance Voting
@@ -127,3 +141,280 @@ type PhaseUpdateInfo struct {
 	UpdatedAt    uint64 `json:"updatedAt"`
 	StakingLimit string `json:"stakingLimit"`
 }
+
+// TransactionRecord immutable history entry of a wallet for a token
+type TransactionRecord struct {
+	DocType      string `json:"docType"`
+	Address      string `json:"address"`
+	Token        string `json:"token"`
+	Direction    string `json:"direction"`
+	Counterparty string `json:"counterparty"`
+	Amount       string `json:"amount"`
+	Fee          string `json:"fee"`
+	Type         string `json:"type"`
+	TxID         string `json:"txId"`
+	Timestamp    uint64 `json:"timestamp"`
+}
+
+// FeeCollection fees routed to a destination during a period
+type FeeCollection struct {
+	Period      string `json:"period"`
+	Destination string `json:"destination"`
+	Amount      string `json:"amount"`
+}
+
+// StakerFeeDistribution distribution of staker fee pool running over several transactions,
+// first pass sums staked coins of active staking addresses and second pass pays their shares
+type StakerFeeDistribution struct {
+	DocType     string `json:"docType"`
+	Pass        string `json:"pass"`
+	PoolAmount  string `json:"poolAmount"`
+	TotalStaked string `json:"totalStaked"`
+	Distributed string `json:"distributed"`
+	NextKey     string `json:"nextKey"`
+	StartedAt   uint64 `json:"startedAt"`
+}
+
+// Sponsorship fees paid by a sponsor wallet on behalf of other users, empty filter matches everything
+type Sponsorship struct {
+	DocType    string   `json:"docType"`
+	ID         string   `json:"id"`
+	Sponsor    string   `json:"sponsor"`
+	UserID     string   `json:"userId"`
+	Operations []string `json:"operations"`
+	Contracts  []string `json:"contracts"`
+	Tokens     []string `json:"tokens"`
+	Cap        string   `json:"cap"`
+	Spent      string   `json:"spent"`
+	Active     bool     `json:"active"`
+	CreatedAt  uint64   `json:"createdAt"`
+}
+
+// TokenListEntry token registry entry of BUSY20 token or NFT/GAME token
+type TokenListEntry struct {
+	Type         string        `json:"type"`
+	ID           uint64        `json:"id,omitempty"`
+	TokenName    string        `json:"tokenName"`
+	TokenSymbol  string        `json:"tokenSymbol"`
+	TokenAddress string        `json:"tokenAddress"`
+	Admin        string        `json:"admin"`
+	TotalSupply  string        `json:"totalSupply"`
+	MaxSupply    string        `json:"maxSupply,omitempty"`
+	Decimals     uint64        `json:"decimals"`
+	Paused       bool          `json:"paused"`
+	MetaData     TokenMetaData `json:"tokenMetaData"`
+}
+
+// FormattedBalance balance in base units and in whole tokens
+type FormattedBalance struct {
+	Address   string `json:"address"`
+	Token     string `json:"token"`
+	Balance   string `json:"balance"`
+	Formatted string `json:"formatted"`
+	Decimals  uint64 `json:"decimals"`
+}
+
+// SupplyAudit recorded total supply of token compared with sum of holder balances
+type SupplyAudit struct {
+	Token            string           `json:"token"`
+	Type             string           `json:"type"`
+	RecordedSupply   string           `json:"recordedSupply"`
+	HoldersSupply    string           `json:"holdersSupply"`
+	Difference       string           `json:"difference"`
+	Mismatch         bool             `json:"mismatch"`
+	Categories       []SupplyCategory `json:"categories"`
+	NegativeBalances []UserAddress    `json:"negativeBalances,omitempty"`
+	LockedVesting    string           `json:"lockedVesting,omitempty"`
+}
+
+// SupplyCategory sum of balances of one kind of holder
+type SupplyCategory struct {
+	Category string `json:"category"`
+	Holders  int    `json:"holders"`
+	Amount   string `json:"amount"`
+}
+
+// HTLC hash time-locked funds of sender held in escrow until recipient reveals preimage or timelock passes
+type HTLC struct {
+	DocType   string `json:"docType"`
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Escrow    string `json:"escrow"`
+	Token     string `json:"token"`
+	Amount    string `json:"amount"`
+	Hashlock  string `json:"hashlock"`
+	Timelock  uint64 `json:"timelock"`
+	Preimage  string `json:"preimage,omitempty"`
+	Status    string `json:"status"`
+	CreatedAt uint64 `json:"createdAt"`
+	SettledAt uint64 `json:"settledAt,omitempty"`
+}
+
+// SwapOffer offer to swap escrowed tokens of maker for tokens of taker
+type SwapOffer struct {
+	DocType     string `json:"docType"`
+	ID          string `json:"id"`
+	Maker       string `json:"maker"`
+	Taker       string `json:"taker,omitempty"`
+	AcceptedBy  string `json:"acceptedBy,omitempty"`
+	Escrow      string `json:"escrow"`
+	OfferToken  string `json:"offerToken"`
+	OfferAmount string `json:"offerAmount"`
+	WantToken   string `json:"wantToken"`
+	WantAmount  string `json:"wantAmount"`
+	Status      string `json:"status"`
+	CreatedAt   uint64 `json:"createdAt"`
+	SettledAt   uint64 `json:"settledAt,omitempty"`
+}
+
+// Order limit order of BusyExchange, remaining base or locked quote of a resting order is held by its escrow address
+type Order struct {
+	DocType     string `json:"docType"`
+	ID          string `json:"id"`
+	Pair        string `json:"pair"`
+	Base        string `json:"base"`
+	Quote       string `json:"quote"`
+	Side        string `json:"side"`
+	Price       string `json:"price"`
+	Amount      string `json:"amount"`
+	Remaining   string `json:"remaining"`
+	LockedQuote string `json:"lockedQuote"`
+	Owner       string `json:"owner"`
+	Escrow      string `json:"escrow"`
+	Status      string `json:"status"`
+	Timestamp   string `json:"timestamp"`
+	CreatedAt   uint64 `json:"createdAt"`
+	UpdatedAt   uint64 `json:"updatedAt,omitempty"`
+}
+
+// Trade fill between a resting maker order and an incoming taker order at maker price
+type Trade struct {
+	DocType      string `json:"docType"`
+	ID           string `json:"id"`
+	Pair         string `json:"pair"`
+	Price        string `json:"price"`
+	Amount       string `json:"amount"`
+	QuoteAmount  string `json:"quoteAmount"`
+	MakerOrderID string `json:"makerOrderId"`
+	TakerOrderID string `json:"takerOrderId"`
+	Maker        string `json:"maker"`
+	Taker        string `json:"taker"`
+	TakerSide    string `json:"takerSide"`
+	CreatedAt    uint64 `json:"createdAt"`
+}
+
+// OrderBookLevel resting amount of base token at a price
+type OrderBookLevel struct {
+	Price  string `json:"price"`
+	Amount string `json:"amount"`
+	Orders int    `json:"orders"`
+}
+
+// OrderBook best price levels of a pair
+type OrderBook struct {
+	Pair string           `json:"pair"`
+	Bids []OrderBookLevel `json:"bids"`
+	Asks []OrderBookLevel `json:"asks"`
+}
+
+// LiquidityPool constant product pool of BUSY20 token and BUSY, reserves are held by pool address
+type LiquidityPool struct {
+	DocType      string `json:"docType"`
+	ID           string `json:"id"`
+	Base         string `json:"base"`
+	Quote        string `json:"quote"`
+	LPToken      string `json:"lpToken"`
+	Address      string `json:"address"`
+	ReserveBase  string `json:"reserveBase"`
+	ReserveQuote string `json:"reserveQuote"`
+	TotalShares  string `json:"totalShares"`
+	FeeBps       uint64 `json:"feeBps"`
+	Creator      string `json:"creator"`
+	CreatedAt    uint64 `json:"createdAt"`
+}
+
+// PoolPrice spot price of pool, price is in BUSY base units per whole base token and inverse price the other way round
+type PoolPrice struct {
+	Pair         string `json:"pair"`
+	Price        string `json:"price"`
+	InversePrice string `json:"inversePrice"`
+	ReserveBase  string `json:"reserveBase"`
+	ReserveQuote string `json:"reserveQuote"`
+}
+
+// SwapQuote amounts of a pool swap
+type SwapQuote struct {
+	Pair      string `json:"pair"`
+	TokenIn   string `json:"tokenIn"`
+	AmountIn  string `json:"amountIn"`
+	TokenOut  string `json:"tokenOut"`
+	AmountOut string `json:"amountOut"`
+}
+
+// MultisigWallet wallet controlled by owners, transfers out of it need approvals of threshold owners
+type MultisigWallet struct {
+	DocType   string   `json:"docType"`
+	Address   string   `json:"address"`
+	Owners    []string `json:"owners"`
+	Threshold uint64   `json:"threshold"`
+	Creator   string   `json:"creator"`
+	CreatedAt uint64   `json:"createdAt"`
+}
+
+// MultisigProposal transfer out of multisig wallet waiting for approvals of its owners
+type MultisigProposal struct {
+	DocType    string   `json:"docType"`
+	ID         string   `json:"id"`
+	Wallet     string   `json:"wallet"`
+	Proposer   string   `json:"proposer"`
+	Recipient  string   `json:"recipient"`
+	Token      string   `json:"token"`
+	Amount     string   `json:"amount"`
+	Approvals  []string `json:"approvals"`
+	Status     string   `json:"status"`
+	ExpiresAt  uint64   `json:"expiresAt"`
+	CreatedAt  uint64   `json:"createdAt"`
+	ExecutedBy string   `json:"executedBy,omitempty"`
+	ExecutedAt uint64   `json:"executedAt,omitempty"`
+}
+
+// Stream payment released linearly to recipient between start and end, unreleased funds are held by escrow address
+type Stream struct {
+	DocType         string `json:"docType"`
+	ID              string `json:"id"`
+	Sender          string `json:"sender"`
+	Recipient       string `json:"recipient"`
+	Token           string `json:"token"`
+	TotalAmount     string `json:"totalAmount"`
+	WithdrawnAmount string `json:"withdrawnAmount"`
+	RefundedAmount  string `json:"refundedAmount,omitempty"`
+	StartAt         uint64 `json:"startAt"`
+	EndAt           uint64 `json:"endAt"`
+	Escrow          string `json:"escrow"`
+	Status          string `json:"status"`
+	CreatedAt       uint64 `json:"createdAt"`
+	CancelledAt     uint64 `json:"cancelledAt,omitempty"`
+}
+
+// StreamInfo stream with amount recipient can withdraw right now
+type StreamInfo struct {
+	Stream
+	WithdrawableAmount string `json:"withdrawableAmount"`
+}
+
+// PagedResult page of records returned by list queries
+type PagedResult struct {
+	Records             interface{} `json:"records"`
+	FetchedRecordsCount int32       `json:"fetchedRecordsCount"`
+	Bookmark            string      `json:"bookmark"`
+}
+
+// FreezeInfo freeze status of wallet set by admin
+type FreezeInfo struct {
+	DocType   string `json:"docType"`
+	Address   string `json:"address"`
+	Frozen    bool   `json:"frozen"`
+	Reason    string `json:"reason"`
+	UpdatedAt uint64 `json:"updatedAt"`
+}
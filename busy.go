@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,27 @@ const TRANSFER_FEE string = "1000000000000000"
 const PHASE1_STAKING_AMOUNT = "10000000000000000000000"
 const BALANCE_EVENT = "BALANCE"
 const DEFAULT_CREDS = "defaultCreds"
+const BATCH_TRANSFER_CONFIG = "BatchTransferConfig"
+const BATCH_FEE_MODE_BATCH = "batch"
+const BATCH_FEE_MODE_LEG = "leg"
+const DEFAULT_MAX_BATCH_RECIPIENTS = 100
+const ALLOWANCE_EVENT = "ALLOWANCE"
+const FREEZE_EVENT = "FREEZE"
+const OWNERSHIP_EVENT = "OWNERSHIP"
+const PAUSE_EVENT = "PAUSE"
+const TOKEN_TYPE_BUSY20 = "BUSY20"
+const allowancePrefix = "owner~spender~token"
+const FEE_SCHEDULE_KEY = "FeeSchedule"
+const DEFAULT_FEE_TYPE = "default"
+
+// FEE_TYPES operation types which are charged from fee schedule
+var FEE_TYPES = []string{
+	"transfer", "batchTransfer", "approve", "transferFrom", "stake", "claim", "unstake", "burn",
+	"vesting", "unlock", "mintToken", "mintGame", "transferBatch", "busyNft", "busynftTransfer",
+	"htlc", "swapOffer", "swapAccept", "placeOrder", "createPool", "addLiquidity", "removeLiquidity",
+	"poolSwap", "multisigCreate", "multisigExecute", "createStream", "withdrawStream", "cancelStream",
+	"tokenVesting",
+}
 
 // Init Initialise chaincocode while deployment
 func (bt *Busy) Init(ctx contractapi.TransactionContextInterface) Response {
@@ -142,23 +164,49 @@ func (bt *Busy) Init(ctx contractapi.TransactionContextInterface) Response {
 		return response
 	}
 
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: wallet.Address,
-		Amount:  supply.String(),
-		Token:   BUSY_COIN_SYMBOL,
-	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s", response.TxID, wallet.Address, token.TokenSymbol), utxoAsBytes)
+	err = addUTXO(ctx, wallet.Address, supply, token.TokenSymbol)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
 		logger.Error(response.Message)
 		return response
 	}
 
-	err = ctx.GetStub().PutState("transferFees", []byte(TRANSFER_FEE))
+	feeScheduleAsBytes, _ := json.Marshal(defaultFeeSchedule(TRANSFER_FEE))
+	err = ctx.GetStub().PutState(FEE_SCHEDULE_KEY, feeScheduleAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while configuring fee schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response
+	}
+
+	batchTransferConfig := BatchTransferConfig{
+		FeeMode:       BATCH_FEE_MODE_BATCH,
+		MaxRecipients: DEFAULT_MAX_BATCH_RECIPIENTS,
+	}
+	configAsBytes, _ = json.Marshal(batchTransferConfig)
+	err = ctx.GetStub().PutState(BATCH_TRANSFER_CONFIG, configAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while configuring batch transfer: %s", err.Error())
+		logger.Error(response.Message)
+		return response
+	}
+
+	feeRoutingConfig := FeeRoutingConfig{
+		Default: FEE_DESTINATION_BURN,
+		Routes:  map[string]string{},
+	}
+	configAsBytes, _ = json.Marshal(feeRoutingConfig)
+	err = ctx.GetStub().PutState(FEE_ROUTING_CONFIG, configAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while configuring fee routing: %s", err.Error())
+		logger.Error(response.Message)
+		return response
+	}
+
+	// fresh ledger has no utxos stored under the old plain keys
+	err = ctx.GetStub().PutState(UTXO_MIGRATION_COMPLETE_KEY, []byte("true"))
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while configuring transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
 		logger.Error(response.Message)
 		return response
 	}
@@ -261,8 +309,362 @@ func (bt *Busy) CreateUser(ctx contractapi.TransactionContextInterface) (*Respon
 	return response, nil
 }
 
-// CreateStakingAddress create new staking address for user
-func (bt *Busy) CreateStakingAddress(ctx contractapi.TransactionContextInterface) (*Response, error) {
+// CreateWallet creates additional wallet for invoker
+func (bt *Busy) CreateWallet(ctx contractapi.TransactionContextInterface) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	userAsBytes, err := ctx.GetStub().GetState(commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching user from blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CWAL001", response.Message)
+	}
+	if userAsBytes == nil {
+		response.Message = "User does not exist"
+		logger.Error(response.Message)
+		return response, generateError(404, "CWAL002", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	wallet := Wallet{
+		DocType:   "wallet",
+		UserID:    commonName,
+		Address:   "B-" + response.TxID,
+		CreatedAt: uint64(now.Seconds),
+	}
+	walletAsBytes, _ := json.Marshal(wallet)
+	err = ctx.GetStub().PutState(wallet.Address, walletAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CWAL003", response.Message)
+	}
+
+	response.Message = "Wallet has been successfully created"
+	response.Success = true
+	response.Data = wallet.Address
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ListWallets list wallets of user with their BUSY balance
+func (bt *Busy) ListWallets(ctx contractapi.TransactionContextInterface, userID string, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "LWAL001", response.Message)
+	}
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, userID)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching user: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(404, "LWAL002", response.Message)
+	}
+
+	var queryString string = fmt.Sprintf(`{
+		"selector": {
+			"userId": "%s",
+			"docType": "wallet"
+		 } 
+	}`, userID)
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(queryString, pageSize, bookmark)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching user wallets: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LWAL003", response.Message)
+	}
+	defer resultIterator.Close()
+
+	wallets := []map[string]interface{}{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching user wallets: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "LWAL003", response.Message)
+		}
+		var wallet Wallet
+		_ = json.Unmarshal(data.Value, &wallet)
+		balance, _, _ := pruneUTXOs(ctx, wallet.Address, BUSY_COIN_SYMBOL)
+		wallets = append(wallets, map[string]interface{}{
+			"address":   wallet.Address,
+			"balance":   balance.String(),
+			"token":     BUSY_COIN_SYMBOL,
+			"createdAt": wallet.CreatedAt,
+			"isDefault": wallet.Address == defaultWalletAddress,
+		})
+	}
+
+	response.Message = "Wallets have been successfully fetched"
+	response.Success = true
+	response.Data = PagedResult{
+		Records:             wallets,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
+	return response, nil
+}
+
+// SetDefaultWallet make one of invoker's wallets the default wallet
+func (bt *Busy) SetDefaultWallet(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	userAsBytes, err := ctx.GetStub().GetState(commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching user from blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "SDWL001", response.Message)
+	}
+	if userAsBytes == nil {
+		response.Message = "User does not exist"
+		logger.Error(response.Message)
+		return response, generateError(404, "SDWL002", response.Message)
+	}
+	var user User
+	_ = json.Unmarshal(userAsBytes, &user)
+
+	walletAddress, err := resolveSenderWallet(ctx, commonName, address)
+	if err != nil || address == "" {
+		response.Message = fmt.Sprintf("Wallet %s does not belong to the user", address)
+		logger.Error(response.Message)
+		return response, generateError(403, "SDWL003", response.Message)
+	}
+	if user.DefaultWallet == walletAddress {
+		response.Message = fmt.Sprintf("Wallet %s is already the default wallet", address)
+		logger.Error(response.Message)
+		return response, generateError(409, "SDWL004", response.Message)
+	}
+
+	user.DefaultWallet = walletAddress
+	userAsBytes, _ = json.Marshal(user)
+	err = ctx.GetStub().PutState(commonName, userAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "SDWL005", response.Message)
+	}
+
+	response.Message = "Default wallet has been successfully updated"
+	response.Success = true
+	response.Data = walletAddress
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// FreezeWallet stop wallet from sending and receiving funds
+func (bt *Busy) FreezeWallet(ctx contractapi.TransactionContextInterface, address string, reason string) (*Response, error) {
+	return updateFreezeStatus(ctx, address, true, reason, "FRZW")
+}
+
+// UnfreezeWallet allow frozen wallet to move funds again
+func (bt *Busy) UnfreezeWallet(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+	return updateFreezeStatus(ctx, address, false, "", "UFZW")
+}
+
+// GetFreezeStatus get freeze status of wallet
+func (bt *Busy) GetFreezeStatus(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	freezeInfo, err := getFreezeInfo(ctx, address)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching freeze status: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GFRZ001", response.Message)
+	}
+
+	response.Message = "Freeze status has been successfully fetched"
+	response.Success = true
+	response.Data = freezeInfo
+	return response, nil
+}
+
+func updateFreezeStatus(ctx contractapi.TransactionContextInterface, address string, frozen bool, reason string, errorPrefix string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update freeze status of wallets"
+		logger.Error(response.Message)
+		return response, generateError(403, errorPrefix+"001", response.Message)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(address)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"002", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", address)
+		logger.Error(response.Message)
+		return response, generateError(404, errorPrefix+"003", response.Message)
+	}
+	freezeInfo, err := getFreezeInfo(ctx, address)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching freeze status: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"004", response.Message)
+	}
+	if freezeInfo.Frozen == frozen {
+		response.Message = fmt.Sprintf("Wallet %s is already in the requested state", address)
+		logger.Error(response.Message)
+		return response, generateError(409, errorPrefix+"005", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	freezeInfo.Frozen = frozen
+	freezeInfo.Reason = reason
+	freezeInfo.UpdatedAt = uint64(now.Seconds)
+	freezeKey, _ := ctx.GetStub().CreateCompositeKey(freezePrefix, []string{address})
+	freezeInfoAsBytes, _ := json.Marshal(freezeInfo)
+	err = ctx.GetStub().PutState(freezeKey, freezeInfoAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"006", response.Message)
+	}
+
+	freezeData := FreezeEvent{
+		Address:       address,
+		Frozen:        frozen,
+		Reason:        reason,
+		TransactionId: response.TxID,
+	}
+	freezeAsBytes, _ := json.Marshal(freezeData)
+	err = ctx.GetStub().SetEvent(FREEZE_EVENT, freezeAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the freeze event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"007", response.Message)
+	}
+
+	if frozen {
+		response.Message = fmt.Sprintf("Wallet %s has been successfully frozen", address)
+	} else {
+		response.Message = fmt.Sprintf("Wallet %s has been successfully unfrozen", address)
+	}
+	response.Success = true
+	response.Data = freezeInfo
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// PauseToken stop every transfer of BUSY20 token, token admin or busy network admin can pause it
+func (bt *Busy) PauseToken(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	return updateTokenPauseStatus(ctx, symbol, true, "PAUS")
+}
+
+// UnpauseToken allow transfers of paused BUSY20 token again
+func (bt *Busy) UnpauseToken(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	return updateTokenPauseStatus(ctx, symbol, false, "UPAU")
+}
+
+func updateTokenPauseStatus(ctx contractapi.TransactionContextInterface, symbol string, paused bool, errorPrefix string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if strings.ToUpper(symbol) == BUSY_COIN_SYMBOL {
+		response.Message = "BUSY can not be paused"
+		logger.Error(response.Message)
+		return response, generateError(412, errorPrefix+"001", response.Message)
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, errorPrefix+"002", response.Message)
+	}
+	allowed, err := isTokenAdminOrNetworkAdmin(ctx, token.Admin)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"003", response.Message)
+	}
+	if !allowed {
+		response.Message = fmt.Sprintf("You are not allowed to update pause status of %s", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(403, errorPrefix+"004", response.Message)
+	}
+	if token.Paused == paused {
+		response.Message = fmt.Sprintf("Token %s is already in the requested state", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(409, errorPrefix+"005", response.Message)
+	}
+
+	token.Paused = paused
+	tokenAsBytes, _ := json.Marshal(token)
+	err = ctx.GetStub().PutState(generateTokenStateAddress(token.TokenSymbol), tokenAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating token on blockchain : %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"006", response.Message)
+	}
+
+	err = sendPauseEvent(ctx, token.TokenSymbol, paused)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pause event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"007", response.Message)
+	}
+
+	if paused {
+		response.Message = fmt.Sprintf("Token %s has been successfully paused", token.TokenSymbol)
+	} else {
+		response.Message = fmt.Sprintf("Token %s has been successfully unpaused", token.TokenSymbol)
+	}
+	response.Success = true
+	response.Data = token
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CreateStakingAddress create new staking address for user, staking amount is taken from fromWallet or default wallet if empty
+func (bt *Busy) CreateStakingAddress(ctx contractapi.TransactionContextInterface, fromWallet string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -287,7 +689,12 @@ func (bt *Busy) CreateStakingAddress(ctx contractapi.TransactionContextInterface
 	fmt.Println(currentPhaseConfig.CurrentStakingLimit)
 	stakingAmount, _ := new(big.Int).SetString(currentPhaseConfig.CurrentStakingLimit, 10)
 	commonName, _ := getCommonName(ctx)
-	defaultWalletAddress, _ := getDefaultWalletAddress(ctx, commonName)
+	defaultWalletAddress, err := resolveSenderWallet(ctx, commonName, fromWallet)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "STK009", response.Message)
+	}
 
 	balance, _ := getBalanceHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
 	if balance.Cmp(stakingAmount) == -1 {
@@ -303,22 +710,22 @@ func (bt *Busy) CreateStakingAddress(ctx contractapi.TransactionContextInterface
 		Balance:   stakingAmount.String(),
 		CreatedAt: uint64(now.Seconds),
 	}
-	txFee, err := getCurrentTxFee(ctx)
+	txFee, err := getTxFee(ctx, "stake")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while getting tx fee: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "STK003", response.Message)
 	}
-	err = transferHelper(ctx, defaultWalletAddress, stakingAddress.Address, stakingAmount, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee))
+	err = transferHelper(ctx, defaultWalletAddress, stakingAddress.Address, stakingAmount, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee), "stake")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while transferring coins to the staking address: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "STK004", response.Message)
 	}
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee))
+	err = routeFee(ctx, "stake", bigTxFee)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while routing transfer fee: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "STK005", response.Message)
 	}
@@ -419,8 +826,8 @@ func (bt *Busy) GetBalance(ctx contractapi.TransactionContextInterface, address
 	return response, nil
 }
 
-// GetUser all the wallet and staking address of user with it's balance
-func (bt *Busy) GetUser(ctx contractapi.TransactionContextInterface, userID string) (*Response, error) {
+// GetFormattedBalance get balance of address in base units along with balance in whole tokens according to decimals of token
+func (bt *Busy) GetFormattedBalance(ctx contractapi.TransactionContextInterface, address string, token string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -428,11 +835,109 @@ func (bt *Busy) GetUser(ctx contractapi.TransactionContextInterface, userID stri
 		Data:    nil,
 	}
 
-	userAsBytes, err := ctx.GetStub().GetState(userID)
-	if userAsBytes == nil {
-		response.Message = "User does not exist"
-		logger.Info(response.Message)
-		return response, fmt.Errorf(response.Message)
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	if strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+		logger.Error(response.Message)
+		return response, generateError(404, "GFBL001", response.Message)
+	}
+	decimals, err := getTokenDecimals(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GFBL001", response.Message)
+	}
+
+	balance, err := getBalanceHelper(ctx, address, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching balance: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GFBL002", response.Message)
+	}
+
+	response.Message = "Balance has been successfully fetched"
+	response.Success = true
+	response.Data = FormattedBalance{
+		Address:   address,
+		Token:     token,
+		Balance:   balance.String(),
+		Formatted: formatAmount(balance, decimals),
+		Decimals:  decimals,
+	}
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetTransactionHistory get history records of wallet address for token, all tokens if token is empty
+func (bt *Busy) GetTransactionHistory(ctx contractapi.TransactionContextInterface, address string, token string, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "GTXH001", response.Message)
+	}
+	attributes := []string{address}
+	if token != "" {
+		attributes = append(attributes, token)
+	}
+	resultIterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(historyPrefix, attributes, pageSize, bookmark)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching transaction history: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GTXH002", response.Message)
+	}
+	defer resultIterator.Close()
+
+	records := []TransactionRecord{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while iterating transaction history: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "GTXH003", response.Message)
+		}
+		var record TransactionRecord
+		_ = json.Unmarshal(data.Value, &record)
+		records = append(records, record)
+	}
+
+	response.Message = "Transaction history has been successfully fetched"
+	response.Success = true
+	response.Data = PagedResult{
+		Records:             records,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
+	return response, nil
+}
+
+// GetUser default wallet and a page of staking addresses of user with their balance
+func (bt *Busy) GetUser(ctx contractapi.TransactionContextInterface, userID string, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, fmt.Errorf(response.Message)
+	}
+	userAsBytes, err := ctx.GetStub().GetState(userID)
+	if userAsBytes == nil {
+		response.Message = "User does not exist"
+		logger.Info(response.Message)
+		return response, fmt.Errorf(response.Message)
 	}
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while fetching user from blockchain: %s", err.Error())
@@ -455,7 +960,7 @@ func (bt *Busy) GetUser(ctx contractapi.TransactionContextInterface, userID stri
 			"docType": "stakingAddr"
 		 } 
 	}`, userID)
-	resultIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(queryString, pageSize, bookmark)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while fetching user wallets: %s", err.Error())
 		logger.Error(response.Message)
@@ -495,7 +1000,11 @@ func (bt *Busy) GetUser(ctx contractapi.TransactionContextInterface, userID stri
 	responseData["messageCoins"] = userDetails.MessageCoins
 	response.Message = "Balance has been successfully fetched"
 	response.Success = true
-	response.Data = responseData
+	response.Data = PagedResult{
+		Records:             responseData,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
 	logger.Info(response.Message)
 	return response, nil
 }
@@ -641,8 +1150,18 @@ func (bt *Busy) SetTokenIssueFee(ctx contractapi.TransactionContextInterface, to
 	return response, nil
 }
 
-// IssueToken issue token in default wallet address of invoker
+// IssueToken issue token in default wallet address of invoker, admin can mint more tokens later without a cap
 func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenName string, symbol string, amount string, decimals uint64, metadata TokenMetaData) (*Response, error) {
+	return issueToken(ctx, tokenName, symbol, amount, decimals, metadata, "")
+}
+
+// IssueTokenWithMaxSupply issue token in default wallet address of invoker, more tokens can be minted later up to maxSupply
+func (bt *Busy) IssueTokenWithMaxSupply(ctx contractapi.TransactionContextInterface, tokenName string, symbol string, amount string, decimals uint64, metadata TokenMetaData, maxSupply string) (*Response, error) {
+	return issueToken(ctx, tokenName, symbol, amount, decimals, metadata, maxSupply)
+}
+
+// issueToken issue BUSY20 token, empty maxSupply means no cap
+func issueToken(ctx contractapi.TransactionContextInterface, tokenName string, symbol string, amount string, decimals uint64, metadata TokenMetaData, maxSupply string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -650,18 +1169,6 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 		Data:    nil,
 	}
 
-	bigAmount, isConverted := new(big.Int).SetString(amount, 10)
-	if !isConverted {
-		response.Message = "Error encountered converting amount"
-		logger.Error(response.Message)
-		return response, generateError(412, "TOK003", response.Message)
-	}
-	if bigAmount.Cmp(bigZero) == 0 {
-		response.Message = "Amount can not be zero"
-		logger.Error(response.Message)
-		return response, generateError(412, "TOK004", response.Message)
-	}
-
 	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
@@ -669,12 +1176,34 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 		return response, generateError(403, "ATU001", response.Message)
 	}
 
-	if decimals > 18 {
+	// decimals are checked before anything is parsed with them
+	if decimals > MAX_TOKEN_DECIMALS {
 		response.Message = "Decimals have to be in range of 1-18"
 		logger.Error(response.Message)
 		return response, generateError(412, "TOK005", response.Message)
 	}
 
+	bigAmount, isConverted := parseAmountWithDecimals(amount, decimals)
+	if !isConverted {
+		response.Message = "Error encountered converting amount"
+		logger.Error(response.Message)
+		return response, generateError(412, "TOK003", response.Message)
+	}
+	if bigAmount.Cmp(bigZero) == 0 {
+		response.Message = "Amount can not be zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "TOK004", response.Message)
+	}
+	if maxSupply != "" {
+		bigMaxSupply, isConverted := parseAmountWithDecimals(maxSupply, decimals)
+		if !isConverted || bigMaxSupply.Cmp(bigAmount) == -1 {
+			response.Message = "Max supply has to be a number not less than amount"
+			logger.Error(response.Message)
+			return response, generateError(412, "TOK026", response.Message)
+		}
+		maxSupply = bigMaxSupply.String()
+	}
+
 	if metadata.Logo == "" || metadata.Type == "" {
 		response.Message = "Invalid Metadata"
 		logger.Error(response.Message)
@@ -711,7 +1240,6 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 	commonName, _ := getCommonName(ctx)
 	tokenFeeString, _ := getTokenIssueFeeForTokenType(ctx, "BUSY20")
 	issueTokenFee, _ := new(big.Int).SetString(tokenFeeString, 10)
-	minusOne, _ := new(big.Int).SetString("-1", 10)
 	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching user's default wallet: %s", err.Error())
@@ -788,6 +1316,7 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 			TokenSymbol:  symbol,
 			Admin:        defaultWalletAddress,
 			TotalSupply:  bigAmount.String(),
+			MaxSupply:    maxSupply,
 			Decimals:     decimals,
 			MetaData:     metadata,
 			TokenAddress: generateTokenStateAddress(symbol),
@@ -822,18 +1351,12 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 		return response, generateError(500, "TOK023", response.Message)
 	}
 
-	err = addUTXO(ctx, defaultWalletAddress, new(big.Int).Set(issueTokenFee).Mul(issueTokenFee, minusOne), BUSY_COIN_SYMBOL)
+	err = chargeFee(ctx, defaultWalletAddress, issueTokenFee, "issueToken")
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while burning fee for issue token: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while charging fee for issue token: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "TOK024", response.Message)
 	}
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Set(issueTokenFee).Mul(minusOne, issueTokenFee))
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while burning issue token fee from total supply: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(400, "TOK025", response.Message)
-	}
 
 	// Sending Balance Event
 	balanceData := BalanceEvent{
@@ -863,8 +1386,8 @@ func (bt *Busy) IssueToken(ctx contractapi.TransactionContextInterface, tokenNam
 	return response, nil
 }
 
-// Transfer transfer given amount from invoker's identity to specified identity
-func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant string, amount string, token string) (*Response, error) {
+// Transfer transfer given amount from invoker's identity to specified identity, fromWallet empty means default wallet
+func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant string, amount string, token string, fromWallet string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -912,15 +1435,18 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 	}
 
 	// Fetch current transfer fee
-	transferFeesAsBytes, err := ctx.GetStub().GetState("transferFees")
+	transferFee, err := getTxFee(ctx, "transfer")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching transfer fee %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "TRA006", response.Message)
 	}
-	bigTransferFee, _ := new(big.Int).SetString(string(transferFeesAsBytes), 10)
+	bigTransferFee, _ := new(big.Int).SetString(transferFee, 10)
 
-	bigAmount, _ := new(big.Int).SetString(amount, 10)
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	bigAmount, _ := parseAmount(ctx, amount, token)
 
 	if bigAmount == nil {
 		response.Message = "Amount is invalid"
@@ -928,15 +1454,12 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 		return response, generateError(412, "TRA007", response.Message)
 	}
 
-	if bigAmount.Cmp(bigZero) == -1 {
+	if bigAmount.Cmp(bigZero) != 1 {
 		response.Message = "Transfer amount is invalid"
 		logger.Error(response.Message)
 		return response, generateError(412, "TRA008", response.Message)
 	}
 
-	if token == "" {
-		token = BUSY_COIN_SYMBOL
-	}
 	sender, _ := getCommonName(ctx)
 	userAsBytes, err := ctx.GetStub().GetState(sender)
 	if userAsBytes == nil {
@@ -949,10 +1472,27 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 		logger.Error(response.Message)
 		return response, generateError(400, "TRA010", response.Message)
 	}
-	var user User
-	_ = json.Unmarshal(userAsBytes, &user)
+	senderWallet, err := resolveSenderWallet(ctx, sender, fromWallet)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "TRA017", response.Message)
+	}
+
+	err = checkWalletNotFrozen(ctx, senderWallet, recipiant)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "TRA018", response.Message)
+	}
+	err = checkTokenNotPaused(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "TRA019", response.Message)
+	}
 
-	if user.DefaultWallet == recipiant {
+	if senderWallet == recipiant {
 		response.Message = "It is not possible to transfer to your address"
 		logger.Error(response.Message)
 		return response, generateError(409, "TRA011", response.Message)
@@ -992,23 +1532,30 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 		bigTransferFee = bigZero
 	}
 
-	err = transferHelper(ctx, user.DefaultWallet, recipiant, bigAmount, token, bigTransferFee)
+	err = autoCompactUTXOs(ctx, recipiant, token)
 	if err != nil {
-		response.Message = "You do not have enough amount to transfer"
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos of recipient: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(400, "TRA014", response.Message)
+		return response, generateError(500, "TRA016", response.Message)
 	}
 
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, bigTransferFee.Mul(minusOne, bigTransferFee))
+	err = transferHelper(ctx, senderWallet, recipiant, bigAmount, token, bigTransferFee, "transfer")
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("You do not have enough amount to transfer: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(400, "TRA020", response.Message)
+	}
+
+	err = routeFee(ctx, "transfer", bigTransferFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transfer fee: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(400, "TRA015", response.Message)
 	}
 
 	userAddresses := []UserAddress{
 		{
-			Address: user.DefaultWallet,
+			Address: senderWallet,
 			Token:   BUSY_COIN_SYMBOL,
 		},
 		{
@@ -1018,14 +1565,14 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 	}
 	if token != BUSY_COIN_SYMBOL {
 		userAddresses = append(userAddresses, UserAddress{
-			Address: user.DefaultWallet,
+			Address: senderWallet,
 			Token:   token,
 		})
 	}
 	// Sending Balance Event
 	balanceData := BalanceEvent{
 		UserAddresses:  userAddresses,
-		TransactionFee: bigTransferFee.Mul(bigTransferFee, minusOne).String(),
+		TransactionFee: bigTransferFee.String(),
 		TransactionId:  response.TxID,
 	}
 	balanceAsBytes, _ := json.Marshal(balanceData)
@@ -1042,8 +1589,8 @@ func (bt *Busy) Transfer(ctx contractapi.TransactionContextInterface, recipiant
 	return response, nil
 }
 
-// GetTotalSupply get total supply of specified token
-func (bt *Busy) GetTotalSupply(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+// BatchTransfer transfer amounts from invoker's default wallet to multiple recipients in a single transaction
+func (bt *Busy) BatchTransfer(ctx contractapi.TransactionContextInterface, recipients []string, amounts []string, token string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1051,188 +1598,286 @@ func (bt *Busy) GetTotalSupply(ctx contractapi.TransactionContextInterface, symb
 		Data:    nil,
 	}
 
-	if symbol == "" {
-		symbol = BUSY_COIN_SYMBOL
+	commonName, _ := getCommonName(ctx)
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if commonName != "busy_network" && err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
 	}
-	var token Token
-	tokenAsBytes, err := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	exists, err := ifTokenExists(ctx, token)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching the details: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "TSUP004", response.Message)
+		return response, generateError(500, "BTRA001", response.Message)
 	}
-	if tokenAsBytes == nil {
-		response.Message = fmt.Sprintf("Symbol %s does not exist", symbol)
+	if !exists || (strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL) {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
 		logger.Error(response.Message)
-		return response, generateError(404, "TSUP005", response.Message)
+		return response, generateError(404, "BTRA002", response.Message)
 	}
-	_ = json.Unmarshal(tokenAsBytes, &token)
 
-	totalSupply, _, err := pruneUTXOs(ctx, TOTAL_SUPPLY_KEY, symbol)
+	config, err := getBatchTransferConfig(ctx)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while pruning utxo: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching batch transfer config: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "TSUP006", response.Message)
+		return response, generateError(500, "BTRA003", response.Message)
 	}
-	token.TotalSupply = totalSupply.String()
-
-	response.Message = "Total supply has been successfully fetched"
-	logger.Info(response.Message)
-	response.Data = fmt.Sprintf("%s %s", token.TotalSupply, symbol)
-	response.Success = true
-	return response, nil
-}
-
-// Burn reduct balance from user wallet and reduce total supply
-func (bt *Busy) Burn(ctx contractapi.TransactionContextInterface, address string, amount string, symbol string) (*Response, error) {
-	response := &Response{
-		TxID:    ctx.GetStub().GetTxID(),
-		Success: false,
-		Message: "",
-		Data:    nil,
+	if len(recipients) == 0 || len(recipients) != len(amounts) {
+		response.Message = "Recipients and amounts must be non empty and of the same length"
+		logger.Error(response.Message)
+		return response, generateError(412, "BTRA004", response.Message)
 	}
-
-	if amount == "0" {
-		response.Message = "It is not possible to burn zero amount"
+	if uint64(len(recipients)) > config.MaxRecipients {
+		response.Message = fmt.Sprintf("Batch can not have more than %d recipients", config.MaxRecipients)
 		logger.Error(response.Message)
-		return response, generateError(400, "BURN001", response.Message)
+		return response, generateError(412, "BTRA005", response.Message)
 	}
-	exists, err := ifTokenExists(ctx, symbol)
+
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching user's default wallet: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "BURN002", response.Message)
+		return response, generateError(404, "BTRA006", response.Message)
 	}
-	if !exists {
-		response.Message = fmt.Sprintf("Symbol %s does not exist", symbol)
-		logger.Error(response.Message)
-		return response, generateError(404, "BURN003", response.Message)
+
+	bigAmounts := make([]*big.Int, len(amounts))
+	totalAmount := new(big.Int).Set(bigZero)
+	for i, recipient := range recipients {
+		bigAmount, ok := parseAmount(ctx, amounts[i], token)
+		if !ok || bigAmount.Cmp(bigZero) != 1 {
+			response.Message = fmt.Sprintf("Amount %s for recipient %s is invalid", amounts[i], recipient)
+			logger.Error(response.Message)
+			return response, generateError(412, "BTRA007", response.Message)
+		}
+		bigAmounts[i] = bigAmount
+		totalAmount = totalAmount.Add(totalAmount, bigAmount)
+
+		if recipient == defaultWalletAddress {
+			response.Message = "It is not possible to transfer to your address"
+			logger.Error(response.Message)
+			return response, generateError(409, "BTRA008", response.Message)
+		}
+		walletAsBytes, err := ctx.GetStub().GetState(recipient)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "BTRA010", response.Message)
+		}
+		if walletAsBytes == nil {
+			response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+			logger.Error(response.Message)
+			return response, generateError(404, "BTRA011", response.Message)
+		}
+		// same as Transfer, only the owner of a staking address can send coins to it
+		var wallet Wallet
+		_ = json.Unmarshal(walletAsBytes, &wallet)
+		if wallet.DocType == "stakingAddr" && wallet.UserID != commonName {
+			response.Message = "It is not possible to make a transfer to the staking addresses"
+			logger.Error(response.Message)
+			return response, generateError(406, "BTRA009", response.Message)
+		}
 	}
 
-	commonName, err := getCommonName(ctx)
+	// Fetch current transfer fee
+	transferFee, err := getTxFee(ctx, "batchTransfer")
 	if err != nil {
-		response.Message = fmt.Sprintf("Error %s fetching common name", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching transfer fee %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "BURN004", response.Message)
+		return response, generateError(500, "BTRA012", response.Message)
 	}
-	err = CheckCredentials(ctx, DEFAULT_CREDS, "true")
-	if commonName != "busy_network" && err != nil {
-		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(403, "ATU001", response.Message)
+	bigTransferFee, _ := new(big.Int).SetString(transferFee, 10)
+	if config.FeeMode == BATCH_FEE_MODE_LEG {
+		bigTransferFee = bigTransferFee.Mul(bigTransferFee, new(big.Int).SetInt64(int64(len(recipients))))
 	}
-	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error %s fetching default wallet", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(400, "BURN005", response.Message)
+	if commonName == "busy_network" {
+		bigTransferFee = new(big.Int).Set(bigZero)
 	}
-	tokenAsBytes, _ := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
-	token := Token{}
-	_ = json.Unmarshal(tokenAsBytes, &token)
 
-	if token.Admin != address {
-		response.Message = fmt.Sprintf("%s not allowed to burn %s", address, symbol)
-		logger.Error(response.Message)
-		return response, generateError(403, "BURN006", response.Message)
+	userAddresses := []UserAddress{
+		{
+			Address: defaultWalletAddress,
+			Token:   BUSY_COIN_SYMBOL,
+		},
 	}
-
-	if defaultWalletAddress != address {
-		response.Message = fmt.Sprintf("Wallet address %s is not allowed to burn", address)
-		logger.Error(response.Message)
-		return response, generateError(403, "BURN007", response.Message)
+	if token != BUSY_COIN_SYMBOL {
+		userAddresses = append(userAddresses, UserAddress{
+			Address: defaultWalletAddress,
+			Token:   token,
+		})
+	}
+	touched := map[string]bool{}
+	for _, recipient := range recipients {
+		if touched[recipient] {
+			continue
+		}
+		touched[recipient] = true
+		err = autoCompactUTXOs(ctx, recipient, token)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while compacting utxos of recipient: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "BTRA013", response.Message)
+		}
+		userAddresses = append(userAddresses, UserAddress{
+			Address: recipient,
+			Token:   token,
+		})
 	}
 
-	balance, err := getBalanceHelper(ctx, address, symbol)
+	err = checkWalletNotFrozen(ctx, append([]string{defaultWalletAddress}, recipients...)...)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while fetching balance: %s", err.Error())
+		response.Message = err.Error()
 		logger.Error(response.Message)
-		return response, generateError(500, "BURN008", response.Message)
+		return response, generateError(423, "BTRA016", response.Message)
 	}
-	bigAmount, _ := new(big.Int).SetString(amount, 10)
-	if balance.Cmp(bigAmount) == -1 {
-		response.Message = "There is not enough balance in the wallet"
+	err = checkTokenNotPaused(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
 		logger.Error(response.Message)
-		return response, generateError(402, "BURN009", response.Message)
+		return response, generateError(423, "BTRA017", response.Message)
 	}
 
-	negetiveBigAmount, _ := new(big.Int).SetString("-"+amount, 10)
-
-	err = addUTXO(ctx, address, negetiveBigAmount, symbol)
+	err = multiTransferHelper(ctx, defaultWalletAddress, recipients, bigAmounts, token, bigTransferFee, "batchTransfer")
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while burning: %s", err.Error())
+		response.Message = fmt.Sprintf("You do not have enough amount to transfer: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "BURN010", response.Message)
+		return response, generateError(400, "BTRA014", response.Message)
 	}
 
-	err = burnTxFee(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	err = routeFee(ctx, "batchTransfer", bigTransferFee)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning tx fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while routing transfer fee: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "BURN011", response.Message)
+		return response, generateError(500, "BTRA015", response.Message)
 	}
 
-	txFee, _ := getCurrentTxFee(ctx)
-	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
-	bigTxFee = new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee)
-	if symbol == BUSY_COIN_SYMBOL {
-		negetiveBigAmount = negetiveBigAmount.Add(negetiveBigAmount, bigTxFee)
-		err = addTotalSupplyUTXO(ctx, symbol, negetiveBigAmount)
-		if err != nil {
-			response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
-			logger.Error(response.Message)
-			return response, generateError(500, "BURN012", response.Message)
-		}
-	} else {
-		err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, bigTxFee)
-		if err != nil {
-			response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
-			logger.Error(response.Message)
-			return response, generateError(500, "BURN012", response.Message)
-		}
+	// Sending Balance Event
+	balanceData := BalanceEvent{
+		UserAddresses:  userAddresses,
+		TransactionFee: bigTransferFee.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while Sending the Balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
 
-		err = addTotalSupplyUTXO(ctx, symbol, negetiveBigAmount)
-		if err != nil {
-			response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
-			logger.Error(response.Message)
-			return response, generateError(500, "BURN012", response.Message)
-		}
+	response.Message = "Batch transfer has been successfully accepted"
+	response.Success = true
+	response.Data = map[string]interface{}{
+		"recipients":     len(recipients),
+		"totalAmount":    totalAmount.String(),
+		"transactionFee": bigTransferFee.String(),
 	}
+	logger.Info(response.Message)
+	return response, nil
+}
 
-	userAddresses := []UserAddress{
-		{
-			Address: defaultWalletAddress,
-			Token:   symbol,
-		},
+// GetBatchTransferConfig get current configuration of batch transfers
+func (bt *Busy) GetBatchTransferConfig(ctx contractapi.TransactionContextInterface) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
 	}
 
-	if symbol != BUSY_COIN_SYMBOL {
-		userAddresses = append(userAddresses, UserAddress{
-			Address: address,
-			Token:   BUSY_COIN_SYMBOL,
-		})
+	config, err := getBatchTransferConfig(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching batch transfer config: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GBTC001", response.Message)
+	}
+
+	response.Message = "Batch transfer config has been successfully fetched"
+	response.Success = true
+	response.Data = config
+	return response, nil
+}
+
+// UpdateBatchTransferConfig update fee mode and maximum number of recipients of batch transfers
+func (bt *Busy) UpdateBatchTransferConfig(ctx contractapi.TransactionContextInterface, feeMode string, maxRecipients uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update batch transfer config"
+		logger.Error(response.Message)
+		return response, generateError(403, "UBTC001", response.Message)
+	}
+	if feeMode != BATCH_FEE_MODE_BATCH && feeMode != BATCH_FEE_MODE_LEG {
+		response.Message = fmt.Sprintf("Invalid fee mode, please select fee mode from [%s, %s]", BATCH_FEE_MODE_BATCH, BATCH_FEE_MODE_LEG)
+		logger.Error(response.Message)
+		return response, generateError(412, "UBTC002", response.Message)
+	}
+	if maxRecipients == 0 {
+		response.Message = "Maximum number of recipients can not be zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "UBTC003", response.Message)
+	}
+
+	config := BatchTransferConfig{
+		FeeMode:       feeMode,
+		MaxRecipients: maxRecipients,
+	}
+	configAsBytes, _ := json.Marshal(config)
+	err := ctx.GetStub().PutState(BATCH_TRANSFER_CONFIG, configAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating batch transfer config: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UBTC004", response.Message)
 	}
+
 	balanceData := BalanceEvent{
-		UserAddresses:  userAddresses,
-		TransactionFee: txFee,
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
 		TransactionId:  response.TxID,
 	}
 	balanceAsBytes, _ := json.Marshal(balanceData)
 	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while Sending the balance event: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
 		logger.Error(response.Message)
 		return response, generateError(500, "BAL001", response.Message)
 	}
 
-	response.Message = "Burn has been successful"
-	logger.Info(response.Message)
+	response.Message = "Batch transfer config has been successfully updated"
 	response.Success = true
+	response.Data = config
+	logger.Info(response.Message)
 	return response, nil
 }
 
-// MultibeneficiaryVestingV1 vesting v1
-func (bt *Busy) MultibeneficiaryVestingV1(ctx contractapi.TransactionContextInterface, recipient string, amount string, numerator uint64, denominator uint64, releaseAt uint64) (*Response, error) {
+func getBatchTransferConfig(ctx contractapi.TransactionContextInterface) (*BatchTransferConfig, error) {
+	configAsBytes, err := ctx.GetStub().GetState(BATCH_TRANSFER_CONFIG)
+	if err != nil {
+		return nil, err
+	}
+	config := BatchTransferConfig{
+		FeeMode:       BATCH_FEE_MODE_BATCH,
+		MaxRecipients: DEFAULT_MAX_BATCH_RECIPIENTS,
+	}
+	if configAsBytes != nil {
+		_ = json.Unmarshal(configAsBytes, &config)
+	}
+	return &config, nil
+}
+
+// Approve allow spender wallet to transfer up to amount of token from invoker's default wallet, zero amount revokes
+func (bt *Busy) Approve(ctx contractapi.TransactionContextInterface, spender string, token string, amount string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1240,116 +1885,2289 @@ func (bt *Busy) MultibeneficiaryVestingV1(ctx contractapi.TransactionContextInte
 		Data:    nil,
 	}
 
-	if amount == "0" {
-		response.Message = "Zero amount can not be vested"
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(412, "VONE001", response.Message)
+		return response, generateError(403, "ATU001", response.Message)
 	}
-
-	// check if wallet already exists
-	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	commonName, _ := getCommonName(ctx)
+	owner, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VONE002", response.Message)
+		return response, generateError(404, "APRV001", response.Message)
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	exists, err := ifTokenExists(ctx, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching the details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "APRV002", response.Message)
+	}
+	if !exists || (strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL) {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+		logger.Error(response.Message)
+		return response, generateError(404, "APRV003", response.Message)
+	}
+
+	bigAmount, ok := parseAmount(ctx, amount, token)
+	if !ok || bigAmount.Cmp(bigZero) == -1 {
+		response.Message = "Amount is invalid"
+		logger.Error(response.Message)
+		return response, generateError(412, "APRV004", response.Message)
+	}
+	if owner == spender {
+		response.Message = "It is not possible to approve your own address"
+		logger.Error(response.Message)
+		return response, generateError(409, "APRV005", response.Message)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(spender)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching spender wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "APRV006", response.Message)
 	}
 	if walletAsBytes == nil {
-		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		response.Message = fmt.Sprintf("Spender %s does not exist", spender)
 		logger.Error(response.Message)
-		return response, generateError(404, "VONE003", response.Message)
+		return response, generateError(404, "APRV007", response.Message)
 	}
 
-	now, _ := ctx.GetStub().GetTxTimestamp()
-	mspid, _ := ctx.GetClientIdentity().GetMSPID()
-	if mspid != "BusyMSP" {
-		response.Message = "You are not allowed to create vesting"
+	txFee, _ := getTxFee(ctx, "approve")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, owner, bigTxFee, "approve", token)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
 		logger.Error(response.Message)
-		return response, generateError(403, "VONE004", response.Message)
+		return response, generateError(402, "APRV008", response.Message)
+	}
+	err = txFeeHelper(ctx, owner, BUSY_COIN_SYMBOL, bigTxFee.String(), "approve", token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while burning transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "APRV009", response.Message)
+	}
+
+	err = setAllowance(ctx, owner, spender, token, bigAmount)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating allowance: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "APRV010", response.Message)
+	}
+
+	allowanceData := AllowanceEvent{
+		Owner:     owner,
+		Spender:   spender,
+		Token:     token,
+		Allowance: bigAmount.String(),
+		UserAddresses: []UserAddress{
+			{
+				Address: owner,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+		},
+		TransactionFee: bigTxFee.String(),
+		TransactionId:  response.TxID,
+	}
+	allowanceAsBytes, _ := json.Marshal(allowanceData)
+	err = ctx.GetStub().SetEvent(ALLOWANCE_EVENT, allowanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the allowance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "APRV011", response.Message)
+	}
+
+	response.Message = "Allowance has been successfully approved"
+	response.Success = true
+	response.Data = allowanceData
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// Allowance get amount of token spender is still allowed to transfer from owner
+func (bt *Busy) Allowance(ctx contractapi.TransactionContextInterface, owner string, spender string, token string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	allowance, err := getAllowance(ctx, owner, spender, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching allowance: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALWC001", response.Message)
+	}
+
+	response.Message = "Allowance has been successfully fetched"
+	response.Success = true
+	response.Data = allowance.String()
+	return response, nil
+}
+
+// TransferFrom transfer amount of token from owner to recipient out of allowance given to invoker's default wallet
+func (bt *Busy) TransferFrom(ctx contractapi.TransactionContextInterface, owner string, recipient string, token string, amount string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
 	}
 	commonName, _ := getCommonName(ctx)
-	if commonName != "busy_network" {
-		response.Message = "You are not allowed to create vesting"
+	spender, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(403, "VONE004", response.Message)
+		return response, generateError(404, "TRFM001", response.Message)
 	}
-	bigAmount, _ := new(big.Int).SetString(amount, 10)
-	if bigAmount.Cmp(bigZero) == 0 {
-		response.Message = "Zero amount can not be vested"
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	bigAmount, ok := parseAmount(ctx, amount, token)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Transfer amount is invalid"
 		logger.Error(response.Message)
-		return response, generateError(400, "VONE001", response.Message)
+		return response, generateError(412, "TRFM002", response.Message)
 	}
-	adminAddress, _ := getDefaultWalletAddress(ctx, commonName)
-	balance, _ := getBalanceHelper(ctx, adminAddress, BUSY_COIN_SYMBOL)
-	if balance.Cmp(bigAmount) == -1 {
-		response.Message = "There is not enough balance in the wallet"
+	if owner == recipient {
+		response.Message = "It is not possible to transfer to the owner address"
 		logger.Error(response.Message)
-		return response, generateError(402, "VONE005", response.Message)
+		return response, generateError(409, "TRFM003", response.Message)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TRFM005", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "TRFM006", response.Message)
+	}
+	// same as Transfer, only the owner of a staking address can receive coins on it
+	var wallet Wallet
+	_ = json.Unmarshal(walletAsBytes, &wallet)
+	if wallet.DocType == "stakingAddr" {
+		var ownerWallet Wallet
+		ownerAsBytes, err := ctx.GetStub().GetState(owner)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "TRFM005", response.Message)
+		}
+		_ = json.Unmarshal(ownerAsBytes, &ownerWallet)
+		if ownerAsBytes == nil || wallet.UserID != ownerWallet.UserID {
+			response.Message = "It is not possible to make a transfer to the staking addresses"
+			logger.Error(response.Message)
+			return response, generateError(406, "TRFM004", response.Message)
+		}
 	}
 
-	lockedTokenAsBytes, _ := ctx.GetStub().GetState(fmt.Sprintf("vesting~%s", recipient))
-	if lockedTokenAsBytes != nil {
-		response.Message = fmt.Sprintf("Vesting for wallet %s already exists", recipient)
+	err = checkWalletNotFrozen(ctx, owner, spender, recipient)
+	if err != nil {
+		response.Message = err.Error()
 		logger.Error(response.Message)
-		return response, generateError(409, "VONE006", response.Message)
+		return response, generateError(423, "TRFM015", response.Message)
 	}
-	if releaseAt < uint64(now.Seconds) {
-		response.Message = "Release time of vesting has to be in the future"
+	err = checkTokenNotPaused(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
 		logger.Error(response.Message)
-		return response, generateError(412, "VONE007", response.Message)
+		return response, generateError(423, "TRFM016", response.Message)
 	}
 
-	totalAmount := new(big.Int).Set(bigAmount)
-	currentVesting, err := calculatePercentage(bigAmount, numerator, denominator)
+	allowance, err := getAllowance(ctx, owner, spender, token)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while calculating vesting percentage: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching allowance: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VONE008", response.Message)
+		return response, generateError(500, "TRFM007", response.Message)
+	}
+	if allowance.Cmp(bigAmount) == -1 {
+		response.Message = fmt.Sprintf("Amount %s exceeds allowance %s", bigAmount.String(), allowance.String())
+		logger.Error(response.Message)
+		return response, generateError(402, "TRFM008", response.Message)
 	}
-	txFee, err := getCurrentTxFee(ctx)
+
+	txFee, _ := getTxFee(ctx, "transferFrom")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, spender, bigTxFee, "transferFrom", token)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "TRFM009", response.Message)
+	}
+
+	err = autoCompactUTXOs(ctx, recipient, token)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while getting tx fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos of recipient: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VONE009", response.Message)
+		return response, generateError(500, "TRFM010", response.Message)
 	}
-	err = transferHelper(ctx, adminAddress, recipient, currentVesting, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee))
+	err = transferHelper(ctx, owner, recipient, bigAmount, token, bigZero, "transferFrom")
 	if err != nil {
-		response.Message = "You do not have enough amount to transfer"
+		response.Message = fmt.Sprintf("Owner does not have enough amount to transfer: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(402, "VONE010", response.Message)
+		return response, generateError(400, "TRFM011", response.Message)
 	}
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee))
+	err = txFeeHelper(ctx, spender, BUSY_COIN_SYMBOL, bigTxFee.String(), "transferFrom", token)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while burning transaction fee: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VONE011", response.Message)
+		return response, generateError(500, "TRFM012", response.Message)
 	}
 
-	lockedToken := LockedTokens{
-		DocType:        "lockedToken",
-		TotalAmount:    totalAmount.String(),
-		ReleasedAmount: currentVesting.String(),
-		StartedAt:      uint64(now.Seconds),
-		ReleaseAt:      releaseAt,
-	}
-	lockedTokenAsBytes, _ = json.Marshal(lockedToken)
-	err = ctx.GetStub().PutState(fmt.Sprintf("vesting~%s", recipient), lockedTokenAsBytes)
+	allowance = allowance.Sub(allowance, bigAmount)
+	err = setAllowance(ctx, owner, spender, token, allowance)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while updating allowance: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VONE012", response.Message)
+		return response, generateError(500, "TRFM013", response.Message)
 	}
 
-	balanceData := BalanceEvent{
+	allowanceData := AllowanceEvent{
+		Owner:     owner,
+		Spender:   spender,
+		Token:     token,
+		Allowance: allowance.String(),
 		UserAddresses: []UserAddress{
+			{
+				Address: owner,
+				Token:   token,
+			},
 			{
 				Address: recipient,
+				Token:   token,
+			},
+			{
+				Address: spender,
 				Token:   BUSY_COIN_SYMBOL,
 			},
 		},
 		TransactionFee: bigTxFee.String(),
 		TransactionId:  response.TxID,
 	}
+	allowanceAsBytes, _ := json.Marshal(allowanceData)
+	err = ctx.GetStub().SetEvent(ALLOWANCE_EVENT, allowanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the allowance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TRFM014", response.Message)
+	}
+
+	response.Message = "Transfer has been successfully accepted"
+	response.Success = true
+	response.Data = allowanceData
+	logger.Info(response.Message)
+	return response, nil
+}
+
+func getAllowance(ctx contractapi.TransactionContextInterface, owner string, spender string, token string) (*big.Int, error) {
+	allowanceKey, err := ctx.GetStub().CreateCompositeKey(allowancePrefix, []string{owner, spender, token})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", allowancePrefix, err)
+	}
+	allowanceAsBytes, err := ctx.GetStub().GetState(allowanceKey)
+	if err != nil {
+		return nil, err
+	}
+	if allowanceAsBytes == nil {
+		return new(big.Int).Set(bigZero), nil
+	}
+	allowance, _ := new(big.Int).SetString(string(allowanceAsBytes), 10)
+	return allowance, nil
+}
+
+func setAllowance(ctx contractapi.TransactionContextInterface, owner string, spender string, token string, amount *big.Int) error {
+	allowanceKey, err := ctx.GetStub().CreateCompositeKey(allowancePrefix, []string{owner, spender, token})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", allowancePrefix, err)
+	}
+	if amount.Cmp(bigZero) == 0 {
+		return ctx.GetStub().DelState(allowanceKey)
+	}
+	return ctx.GetStub().PutState(allowanceKey, []byte(amount.String()))
+}
+
+// MintToken mint more tokens of BUSY20 token to recipient, only token admin can mint and total supply can not exceed max supply
+func (bt *Busy) MintToken(ctx contractapi.TransactionContextInterface, symbol string, recipient string, amount string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+
+	bigAmount, isConverted := parseAmount(ctx, amount, symbol)
+	if !isConverted || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "MINT001", response.Message)
+	}
+	if strings.ToUpper(symbol) == BUSY_COIN_SYMBOL {
+		response.Message = "BUSY can not be minted"
+		logger.Error(response.Message)
+		return response, generateError(412, "MINT002", response.Message)
+	}
+
+	var token Token
+	tokenAsBytes, err := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT003", response.Message)
+	}
+	if tokenAsBytes == nil {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", symbol)
+		logger.Error(response.Message)
+		return response, generateError(404, "MINT004", response.Message)
+	}
+	_ = json.Unmarshal(tokenAsBytes, &token)
+
+	commonName, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT005", response.Message)
+	}
+	if token.Admin != defaultWalletAddress {
+		response.Message = fmt.Sprintf("Only admin of %s can mint tokens", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "MINT006", response.Message)
+	}
+
+	recipientAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching recipient wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT007", response.Message)
+	}
+	recipientWallet := Wallet{}
+	if recipientAsBytes != nil {
+		_ = json.Unmarshal(recipientAsBytes, &recipientWallet)
+	}
+	if recipientWallet.DocType != "wallet" {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "MINT008", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, defaultWalletAddress, recipient)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "MINT009", response.Message)
+	}
+
+	if token.MaxSupply != "" {
+		totalSupply, _, err := pruneUTXOs(ctx, TOTAL_SUPPLY_KEY, token.TokenSymbol)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching total supply: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MINT010", response.Message)
+		}
+		maxSupply, _ := new(big.Int).SetString(token.MaxSupply, 10)
+		if new(big.Int).Add(totalSupply, bigAmount).Cmp(maxSupply) == 1 {
+			response.Message = fmt.Sprintf("Minting %s would exceed max supply %s of %s, current total supply is %s", bigAmount.String(), token.MaxSupply, token.TokenSymbol, totalSupply.String())
+			logger.Error(response.Message)
+			return response, generateError(412, "MINT011", response.Message)
+		}
+	}
+
+	txFee, _ := getTxFee(ctx, "mintToken")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, defaultWalletAddress, bigTxFee, "mintToken", token.TokenSymbol)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "MINT012", response.Message)
+	}
+
+	err = addUTXO(ctx, recipient, bigAmount, token.TokenSymbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while generating UTXO for minted tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT013", response.Message)
+	}
+	err = addTotalSupplyUTXO(ctx, token.TokenSymbol, bigAmount)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT014", response.Message)
+	}
+	err = chargeFee(ctx, defaultWalletAddress, bigTxFee, "mintToken", token.TokenSymbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MINT015", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: defaultWalletAddress,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+			{
+				Address: recipient,
+				Token:   token.TokenSymbol,
+			},
+		},
+		TransactionFee: bigTxFee.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s %s has been successfully minted to %s", bigAmount.String(), token.TokenSymbol, recipient)
+	response.Success = true
+	response.Data = token
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// TransferTokenAdmin propose newAdmin as admin of BUSY20 token, it takes effect once newAdmin accepts it.
+// Empty newAdmin cancels pending proposal
+func (bt *Busy) TransferTokenAdmin(ctx contractapi.TransactionContextInterface, symbol string, newAdmin string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "TTAD001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTAD002", response.Message)
+	}
+	if token.Admin == "" || token.Admin != defaultWalletAddress {
+		response.Message = fmt.Sprintf("Only admin of %s can transfer the admin role", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "TTAD003", response.Message)
+	}
+	if newAdmin == token.Admin {
+		response.Message = fmt.Sprintf("%s is already admin of %s", newAdmin, token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(409, "TTAD004", response.Message)
+	}
+	if newAdmin != "" {
+		err = checkNewTokenOwner(ctx, newAdmin)
+		if err != nil {
+			response.Message = err.Error()
+			logger.Error(response.Message)
+			return response, generateError(412, "TTAD005", response.Message)
+		}
+	}
+
+	token.PendingAdmin = newAdmin
+	tokenAsBytes, _ := json.Marshal(token)
+	err = ctx.GetStub().PutState(generateTokenStateAddress(token.TokenSymbol), tokenAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating token on blockchain : %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTAD006", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, token.TokenSymbol, token.Admin, token.Admin, newAdmin)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTAD007", response.Message)
+	}
+
+	if newAdmin == "" {
+		response.Message = fmt.Sprintf("Pending admin transfer of %s has been cancelled", token.TokenSymbol)
+	} else {
+		response.Message = fmt.Sprintf("Admin of %s has been proposed to %s", token.TokenSymbol, newAdmin)
+	}
+	response.Success = true
+	response.Data = token
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// AcceptTokenAdmin accept admin role of BUSY20 token proposed to one of invoker's wallets
+func (bt *Busy) AcceptTokenAdmin(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ATAD001", response.Message)
+	}
+	if token.PendingAdmin == "" {
+		response.Message = fmt.Sprintf("There is no pending admin transfer for %s", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(409, "ATAD002", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	_, err = resolveSenderWallet(ctx, commonName, token.PendingAdmin)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(403, "ATAD003", response.Message)
+	}
+
+	previousAdmin := token.Admin
+	token.Admin = token.PendingAdmin
+	token.PendingAdmin = ""
+	tokenAsBytes, _ := json.Marshal(token)
+	err = ctx.GetStub().PutState(generateTokenStateAddress(token.TokenSymbol), tokenAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating token on blockchain : %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ATAD004", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, token.TokenSymbol, previousAdmin, token.Admin, "")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ATAD005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s is now admin of %s", token.Admin, token.TokenSymbol)
+	response.Success = true
+	response.Data = token
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RenounceTokenAdmin give up admin role of BUSY20 token, nobody can mint or burn it afterwards
+func (bt *Busy) RenounceTokenAdmin(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "RTAD001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTAD002", response.Message)
+	}
+	if token.Admin == "" || token.Admin != defaultWalletAddress {
+		response.Message = fmt.Sprintf("Only admin of %s can renounce the admin role", token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "RTAD003", response.Message)
+	}
+
+	previousAdmin := token.Admin
+	token.Admin = ""
+	token.PendingAdmin = ""
+	tokenAsBytes, _ := json.Marshal(token)
+	err = ctx.GetStub().PutState(generateTokenStateAddress(token.TokenSymbol), tokenAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating token on blockchain : %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTAD004", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, token.TokenSymbol, previousAdmin, "", "")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTAD005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Admin role of %s has been renounced", token.TokenSymbol)
+	response.Success = true
+	response.Data = token
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ListTokens list issued BUSY20 tokens and NFT/GAME tokens, tokenType is BUSY20, NFT or GAME and admin is admin wallet, empty matches everything
+func (bt *Busy) ListTokens(ctx contractapi.TransactionContextInterface, tokenType string, admin string, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "LTOK001", response.Message)
+	}
+	tokenType = strings.ToUpper(tokenType)
+	selector := map[string]interface{}{}
+	switch tokenType {
+	case TOKEN_TYPE_BUSY20:
+		selector["docType"] = "token"
+		if admin != "" {
+			selector["admin"] = admin
+		}
+	case "NFT", "GAME":
+		selector["tokenMetadata.type"] = tokenType
+		if admin != "" {
+			selector["account"] = admin
+		}
+	case "":
+		selector["tokenAddress"] = map[string]interface{}{"$gt": nil}
+		if admin != "" {
+			selector["$or"] = []map[string]interface{}{{"admin": admin}, {"account": admin}}
+		}
+	default:
+		response.Message = fmt.Sprintf("Invalid token type %s, it must be one of %s, NFT or GAME", tokenType, TOKEN_TYPE_BUSY20)
+		logger.Error(response.Message)
+		return response, generateError(412, "LTOK002", response.Message)
+	}
+	queryAsBytes, _ := json.Marshal(map[string]interface{}{"selector": selector})
+
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(string(queryAsBytes), pageSize, bookmark)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LTOK003", response.Message)
+	}
+	defer resultIterator.Close()
+
+	tokens := []TokenListEntry{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching tokens: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "LTOK003", response.Message)
+		}
+		entry, err := getTokenListEntry(ctx, data.Value)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while retrieving token %s: %s", data.Key, err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "LTOK004", response.Message)
+		}
+		tokens = append(tokens, *entry)
+	}
+
+	response.Message = "Tokens have been successfully fetched"
+	response.Success = true
+	response.Data = PagedResult{
+		Records:             tokens,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
+	return response, nil
+}
+
+// getTokenListEntry registry entry of BUSY20 token or NFT/GAME token document with its current supply
+func getTokenListEntry(ctx contractapi.TransactionContextInterface, tokenAsBytes []byte) (*TokenListEntry, error) {
+	var docType struct {
+		DocType string `json:"docType"`
+	}
+	_ = json.Unmarshal(tokenAsBytes, &docType)
+
+	if docType.DocType == "token" {
+		var token Token
+		if err := json.Unmarshal(tokenAsBytes, &token); err != nil {
+			return nil, err
+		}
+		totalSupply, _, err := pruneUTXOs(ctx, TOTAL_SUPPLY_KEY, token.TokenSymbol)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenListEntry{
+			Type:         TOKEN_TYPE_BUSY20,
+			ID:           token.ID,
+			TokenName:    token.TokenName,
+			TokenSymbol:  token.TokenSymbol,
+			TokenAddress: token.TokenAddress,
+			Admin:        token.Admin,
+			TotalSupply:  totalSupply.String(),
+			MaxSupply:    token.MaxSupply,
+			Decimals:     token.Decimals,
+			Paused:       token.Paused,
+			MetaData:     token.MetaData,
+		}, nil
+	}
+
+	var busyTokensInfo BusyTokensInfo
+	if err := json.Unmarshal(tokenAsBytes, &busyTokensInfo); err != nil {
+		return nil, err
+	}
+	totalSupply, _, err := pruneUTXOs(ctx, TOTAL_SUPPLY_KEY_NFT, busyTokensInfo.TokenSymbol)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenListEntry{
+		Type:         busyTokensInfo.MetaData.Type,
+		TokenName:    busyTokensInfo.MetaData.Name,
+		TokenSymbol:  busyTokensInfo.TokenSymbol,
+		TokenAddress: busyTokensInfo.TokenAddress,
+		Admin:        busyTokensInfo.Account,
+		TotalSupply:  totalSupply.String(),
+		Paused:       busyTokensInfo.Paused,
+		MetaData:     busyTokensInfo.MetaData,
+	}, nil
+}
+
+// GetTotalSupply get total supply of specified token
+func (bt *Busy) GetTotalSupply(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if symbol == "" {
+		symbol = BUSY_COIN_SYMBOL
+	}
+	var token Token
+	tokenAsBytes, err := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TSUP004", response.Message)
+	}
+	if tokenAsBytes == nil {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", symbol)
+		logger.Error(response.Message)
+		return response, generateError(404, "TSUP005", response.Message)
+	}
+	_ = json.Unmarshal(tokenAsBytes, &token)
+
+	totalSupply, _, err := pruneUTXOs(ctx, TOTAL_SUPPLY_KEY, symbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while pruning utxo: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TSUP006", response.Message)
+	}
+	token.TotalSupply = totalSupply.String()
+
+	response.Message = "Total supply has been successfully fetched"
+	logger.Info(response.Message)
+	response.Data = fmt.Sprintf("%s %s", token.TotalSupply, symbol)
+	response.Success = true
+	return response, nil
+}
+
+// AuditSupply compare recorded total supply of BUSY20 or NFT/GAME token with the sum of every holder balance.
+// Scans every balance of the ledger, it is meant to be evaluated and not submitted.
+func (bt *Busy) AuditSupply(ctx contractapi.TransactionContextInterface, token string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	audit := SupplyAudit{
+		Token:      token,
+		Categories: []SupplyCategory{},
+	}
+	var holderBalances map[string]*big.Int
+	var recordedSupply *big.Int
+
+	busy20Exists, err := ifTokenExists(ctx, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AUDS001", response.Message)
+	}
+	if busy20Exists {
+		audit.Type = TOKEN_TYPE_BUSY20
+		recordedSupply, _, err = pruneUTXOs(ctx, TOTAL_SUPPLY_KEY, token)
+		if err == nil {
+			holderBalances, err = getUTXOBalancesOfToken(ctx, token)
+		}
+	} else {
+		busyTokensInfo, infoErr := getBusyTokensInfo(ctx, token)
+		if infoErr != nil {
+			response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+			logger.Error(response.Message)
+			return response, generateError(404, "AUDS002", response.Message)
+		}
+		audit.Type = busyTokensInfo.MetaData.Type
+		recordedSupply, _, err = pruneUTXOs(ctx, TOTAL_SUPPLY_KEY_NFT, token)
+		if err == nil {
+			holderBalances, err = getBusyTokensBalancesOfToken(ctx, token)
+		}
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while summing balances: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AUDS003", response.Message)
+	}
+
+	holdersSupply := new(big.Int).Set(bigZero)
+	categoryIndex := map[string]int{}
+	for address, balance := range holderBalances {
+		if address == TOTAL_SUPPLY_KEY || address == TOTAL_SUPPLY_KEY_NFT || balance.Sign() == 0 {
+			continue
+		}
+		category := getHolderCategory(address)
+		idx, ok := categoryIndex[category]
+		if !ok {
+			idx = len(audit.Categories)
+			categoryIndex[category] = idx
+			audit.Categories = append(audit.Categories, SupplyCategory{Category: category, Amount: bigZero.String()})
+		}
+		amount, _ := new(big.Int).SetString(audit.Categories[idx].Amount, 10)
+		audit.Categories[idx].Amount = amount.Add(amount, balance).String()
+		audit.Categories[idx].Holders++
+		if balance.Sign() == -1 {
+			audit.NegativeBalances = append(audit.NegativeBalances, UserAddress{Address: address, Token: token})
+		}
+		holdersSupply.Add(holdersSupply, balance)
+	}
+	sort.Slice(audit.Categories, func(i, j int) bool {
+		return audit.Categories[i].Category < audit.Categories[j].Category
+	})
+	sort.Slice(audit.NegativeBalances, func(i, j int) bool {
+		return audit.NegativeBalances[i].Address < audit.NegativeBalances[j].Address
+	})
+
+	if token == BUSY_COIN_SYMBOL {
+		lockedVesting, err := getLockedVestingAmount(ctx)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching vestings: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "AUDS004", response.Message)
+		}
+		audit.LockedVesting = lockedVesting.String()
+	}
+
+	difference := new(big.Int).Sub(recordedSupply, holdersSupply)
+	audit.RecordedSupply = recordedSupply.String()
+	audit.HoldersSupply = holdersSupply.String()
+	audit.Difference = difference.String()
+	audit.Mismatch = difference.Sign() != 0
+
+	if audit.Mismatch {
+		response.Message = fmt.Sprintf("Recorded supply of %s differs from holder balances by %s", token, audit.Difference)
+	} else {
+		response.Message = fmt.Sprintf("Recorded supply of %s matches holder balances", token)
+	}
+	response.Success = true
+	response.Data = audit
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CompactUTXOs merge all the utxos of the address for token into a single utxo, total supply stays the same
+func (bt *Busy) CompactUTXOs(ctx contractapi.TransactionContextInterface, address string, token string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	commonName, _ := getCommonName(ctx)
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if commonName != "busy_network" && err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "CUTX001", response.Message)
+	}
+
+	if address == TOTAL_SUPPLY_KEY || address == TOTAL_SUPPLY_KEY_NFT {
+		if commonName != "busy_network" {
+			response.Message = "You are not allowed to compact total supply"
+			logger.Error(response.Message)
+			return response, generateError(403, "CUTX002", response.Message)
+		}
+	} else {
+		walletAsBytes, err := ctx.GetStub().GetState(address)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "CUTX003", response.Message)
+		}
+		if walletAsBytes == nil {
+			response.Message = fmt.Sprintf("Wallet %s does not exist", address)
+			logger.Error(response.Message)
+			return response, generateError(404, "CUTX004", response.Message)
+		}
+		var wallet Wallet
+		_ = json.Unmarshal(walletAsBytes, &wallet)
+		if wallet.UserID != commonName && commonName != "busy_network" {
+			response.Message = fmt.Sprintf("You are not allowed to compact utxos of %s", address)
+			logger.Error(response.Message)
+			return response, generateError(403, "CUTX005", response.Message)
+		}
+	}
+
+	var exists bool
+	if address == TOTAL_SUPPLY_KEY_NFT {
+		busyTokensInfoAsBytes, err := ctx.GetStub().GetState(generateTokenAddress(token))
+		exists = busyTokensInfoAsBytes != nil
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching the details: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "CUTX006", response.Message)
+		}
+	} else {
+		exists, err = ifTokenExists(ctx, token)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching the details: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "CUTX006", response.Message)
+		}
+	}
+	if !exists || (strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL) {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+		logger.Error(response.Message)
+		return response, generateError(404, "CUTX007", response.Message)
+	}
+
+	balance, compacted, err := compactUTXOs(ctx, address, token, 2)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CUTX008", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: address,
+				Token:   token,
+			},
+		},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%d utxos have been successfully compacted", compacted)
+	response.Success = true
+	response.Data = map[string]interface{}{
+		"address":   address,
+		"token":     token,
+		"balance":   balance.String(),
+		"compacted": compacted,
+	}
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// MigrateUTXOs move utxos stored under plain keys to the utxo composite keys, limit is number of
+// world state records scanned starting from startKey, call again with returned nextKey until it is empty
+func (bt *Busy) MigrateUTXOs(ctx contractapi.TransactionContextInterface, startKey string, limit uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to migrate utxos"
+		logger.Error(response.Message)
+		return response, generateError(403, "MUTX001", response.Message)
+	}
+	if limit == 0 {
+		response.Message = "Limit can not be zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "MUTX002", response.Message)
+	}
+	migrated, err := isUTXOMigrationComplete(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching migration status: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MUTX003", response.Message)
+	}
+	if migrated {
+		response.Message = "UTXOs have been already migrated"
+		logger.Error(response.Message)
+		return response, generateError(409, "MUTX004", response.Message)
+	}
+
+	// range query over plain keys never returns composite keys
+	resultIterator, err := ctx.GetStub().GetStateByRange(startKey, "")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching world state: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MUTX005", response.Message)
+	}
+	defer resultIterator.Close()
+
+	var scanned uint64
+	var converted uint64
+	nextKey := ""
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while iterating world state: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MUTX005", response.Message)
+		}
+		if scanned == limit {
+			nextKey = data.Key
+			break
+		}
+		scanned++
+
+		var utxo UTXO
+		if json.Unmarshal(data.Value, &utxo) != nil || utxo.DocType != "utxo" {
+			continue
+		}
+		bigAmount, ok := new(big.Int).SetString(utxo.Amount, 10)
+		if !ok {
+			response.Message = fmt.Sprintf("Invalid amount in utxo %s", data.Key)
+			logger.Error(response.Message)
+			return response, generateError(500, "MUTX006", response.Message)
+		}
+		err = putUTXO(ctx, utxo.Address, bigAmount, utxo.Token, "migrated~"+data.Key)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while storing utxo %s: %s", data.Key, err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MUTX007", response.Message)
+		}
+		err = ctx.GetStub().DelState(data.Key)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while deleting utxo %s: %s", data.Key, err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MUTX007", response.Message)
+		}
+		converted++
+	}
+
+	if nextKey == "" {
+		err = ctx.GetStub().PutState(UTXO_MIGRATION_COMPLETE_KEY, []byte("true"))
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while updating migration status: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MUTX008", response.Message)
+		}
+	}
+
+	response.Message = fmt.Sprintf("%d utxos have been successfully migrated", converted)
+	response.Success = true
+	response.Data = map[string]interface{}{
+		"scanned":   scanned,
+		"converted": converted,
+		"nextKey":   nextKey,
+	}
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// Burn reduct balance from user wallet and reduce total supply
+func (bt *Busy) Burn(ctx contractapi.TransactionContextInterface, address string, amount string, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	exists, err := ifTokenExists(ctx, symbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN002", response.Message)
+	}
+	if !exists {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", symbol)
+		logger.Error(response.Message)
+		return response, generateError(404, "BURN003", response.Message)
+	}
+	bigAmount, ok := parseAmount(ctx, amount, symbol)
+	if !ok || bigAmount.Sign() != 1 {
+		response.Message = "Burn amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(400, "BURN001", response.Message)
+	}
+
+	commonName, err := getCommonName(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error %s fetching common name", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN004", response.Message)
+	}
+	err = CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if commonName != "busy_network" && err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error %s fetching default wallet", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(400, "BURN005", response.Message)
+	}
+	tokenAsBytes, _ := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
+	token := Token{}
+	_ = json.Unmarshal(tokenAsBytes, &token)
+
+	if token.Admin != address {
+		response.Message = fmt.Sprintf("%s not allowed to burn %s", address, symbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "BURN006", response.Message)
+	}
+
+	if defaultWalletAddress != address {
+		response.Message = fmt.Sprintf("Wallet address %s is not allowed to burn", address)
+		logger.Error(response.Message)
+		return response, generateError(403, "BURN007", response.Message)
+	}
+
+	balance, err := getBalanceHelper(ctx, address, symbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching balance: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN008", response.Message)
+	}
+	if balance.Cmp(bigAmount) == -1 {
+		response.Message = "There is not enough balance in the wallet"
+		logger.Error(response.Message)
+		return response, generateError(402, "BURN009", response.Message)
+	}
+
+	err = autoCompactUTXOs(ctx, address, symbol)
+	if err == nil && symbol != BUSY_COIN_SYMBOL {
+		err = autoCompactUTXOs(ctx, address, BUSY_COIN_SYMBOL)
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN013", response.Message)
+	}
+
+	negetiveBigAmount := new(big.Int).Neg(bigAmount)
+
+	err = addUTXO(ctx, address, negetiveBigAmount, symbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while burning: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN010", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "burn")
+	err = chargeTxFee(ctx, defaultWalletAddress, "burn", symbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN011", response.Message)
+	}
+
+	err = addTotalSupplyUTXO(ctx, symbol, negetiveBigAmount)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BURN012", response.Message)
+	}
+
+	userAddresses := []UserAddress{
+		{
+			Address: defaultWalletAddress,
+			Token:   symbol,
+		},
+	}
+
+	if symbol != BUSY_COIN_SYMBOL {
+		userAddresses = append(userAddresses, UserAddress{
+			Address: address,
+			Token:   BUSY_COIN_SYMBOL,
+		})
+	}
+	balanceData := BalanceEvent{
+		UserAddresses:  userAddresses,
+		TransactionFee: txFee,
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while Sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = "Burn has been successful"
+	logger.Info(response.Message)
+	response.Success = true
+	return response, nil
+}
+
+// MultibeneficiaryVestingV1 vesting v1
+func (bt *Busy) MultibeneficiaryVestingV1(ctx contractapi.TransactionContextInterface, recipient string, amount string, numerator uint64, denominator uint64, releaseAt uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if amount == "0" {
+		response.Message = "Zero amount can not be vested"
+		logger.Error(response.Message)
+		return response, generateError(412, "VONE001", response.Message)
+	}
+
+	// check if wallet already exists
+	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VONE002", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "VONE003", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	if mspid != "BusyMSP" {
+		response.Message = "You are not allowed to create vesting"
+		logger.Error(response.Message)
+		return response, generateError(403, "VONE004", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if commonName != "busy_network" {
+		response.Message = "You are not allowed to create vesting"
+		logger.Error(response.Message)
+		return response, generateError(403, "VONE004", response.Message)
+	}
+	bigAmount, _ := parseAmount(ctx, amount, BUSY_COIN_SYMBOL)
+	if bigAmount.Cmp(bigZero) == 0 {
+		response.Message = "Zero amount can not be vested"
+		logger.Error(response.Message)
+		return response, generateError(400, "VONE001", response.Message)
+	}
+	adminAddress, _ := getDefaultWalletAddress(ctx, commonName)
+	balance, _ := getBalanceHelper(ctx, adminAddress, BUSY_COIN_SYMBOL)
+	if balance.Cmp(bigAmount) == -1 {
+		response.Message = "There is not enough balance in the wallet"
+		logger.Error(response.Message)
+		return response, generateError(402, "VONE005", response.Message)
+	}
+
+	if releaseAt < uint64(now.Seconds) {
+		response.Message = "Release time of vesting has to be in the future"
+		logger.Error(response.Message)
+		return response, generateError(412, "VONE007", response.Message)
+	}
+
+	totalAmount := new(big.Int).Set(bigAmount)
+	currentVesting, err := calculatePercentage(bigAmount, numerator, denominator)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while calculating vesting percentage: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VONE008", response.Message)
+	}
+	txFee, err := getTxFee(ctx, "vesting")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while getting tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VONE009", response.Message)
+	}
+	err = transferHelper(ctx, adminAddress, recipient, currentVesting, BUSY_COIN_SYMBOL, new(big.Int).Set(bigTxFee), "vesting")
+	if err != nil {
+		response.Message = "You do not have enough amount to transfer"
+		logger.Error(response.Message)
+		return response, generateError(402, "VONE010", response.Message)
+	}
+	err = routeFee(ctx, "vesting", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transfer fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VONE011", response.Message)
+	}
+
+	lockedToken := LockedTokens{
+		DocType:        "lockedToken",
+		ID:             response.TxID,
+		Address:        recipient,
+		Grantor:        adminAddress,
+		TotalAmount:    totalAmount.String(),
+		ReleasedAmount: currentVesting.String(),
+		StartedAt:      uint64(now.Seconds),
+		ReleaseAt:      releaseAt,
+	}
+	err = putVestingSchedule(ctx, &lockedToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VONE012", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: recipient,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+		},
+		TransactionFee: bigTxFee.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+	response.Message = "Vesting has been scheduled successfully"
+	logger.Info(response.Message)
+	response.Success = true
+	return response, nil
+}
+
+// MultibeneficiaryVestingV2 vesting v2
+func (bt *Busy) MultibeneficiaryVestingV2(ctx contractapi.TransactionContextInterface, recipient string, amount string, startAt uint64, releaseAt uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if amount == "0" {
+		response.Message = "Zero amount can not be vested"
+		logger.Error(response.Message)
+		return response, generateError(412, "VTWO001", response.Message)
+	}
+
+	// check if wallet already exists
+	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VTWO002", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "VTWO003", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	if mspid != "BusyMSP" {
+		response.Message = "You are not allowed to create vesting"
+		logger.Error(response.Message)
+		return response, generateError(403, "VTWO004", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if commonName != "busy_network" {
+		response.Message = "You are not allowed to create vesting"
+		logger.Error(response.Message)
+		return response, generateError(404, "VTWO004", response.Message)
+	}
+	bigAmount, _ := parseAmount(ctx, amount, BUSY_COIN_SYMBOL)
+	if bigAmount.Cmp(bigZero) == 0 {
+		response.Message = "Zero amount can not be vested"
+		logger.Error(response.Message)
+		return response, generateError(412, "VTWO001", response.Message)
+	}
+	adminAddress, _ := getDefaultWalletAddress(ctx, commonName)
+	balance, _ := getBalanceHelper(ctx, adminAddress, BUSY_COIN_SYMBOL)
+	if balance.Cmp(bigAmount) == -1 {
+		response.Message = "There is not enough balance in the wallet"
+		logger.Error(response.Message)
+		return response, generateError(402, "VTWO005", response.Message)
+	}
+	if releaseAt < startAt {
+		response.Message = "Release time of vesting has to be greater then start time"
+		logger.Error(response.Message)
+		return response, generateError(412, "VTWO006", response.Message)
+	}
+
+	if releaseAt < uint64(now.Seconds) {
+		response.Message = "Release time of vesting has to be in the future"
+		logger.Error(response.Message)
+		return response, generateError(412, "VTWO008", response.Message)
+	}
+
+	if startAt < uint64(now.Seconds) {
+		response.Message = "Start time of vesting has to be in the future"
+		logger.Error(response.Message)
+		return response, generateError(412, "VTWO009", response.Message)
+	}
+
+	totalAmount := new(big.Int).Set(bigAmount)
+	lockedToken := LockedTokens{
+		DocType:        "lockedToken",
+		ID:             response.TxID,
+		Address:        recipient,
+		Grantor:        adminAddress,
+		TotalAmount:    totalAmount.String(),
+		ReleasedAmount: "0",
+		StartedAt:      startAt,
+		ReleaseAt:      releaseAt,
+	}
+	err = putVestingSchedule(ctx, &lockedToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VTWO010", response.Message)
+	}
+	sender, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, sender)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VTWO011", response.Message)
+	}
+	err = chargeTxFee(ctx, defaultWalletAddress, "vesting")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VTWO012", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "vesting")
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: recipient,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+		},
+		TransactionFee: txFee,
+		TransactionId:  response.TxID,
+	}
+
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+	response.Message = "Vesting has been scheduled successfully"
+	logger.Info(response.Message)
+	response.Success = true
+	return response, nil
+}
+
+// CreateVestingSchedule lock amount of BUSY for recipient and release it linearly between startAt and releaseAt.
+// Nothing is released before cliffAt when it is not zero, revocable schedule can be revoked by RevokeVesting
+func (bt *Busy) CreateVestingSchedule(ctx contractapi.TransactionContextInterface, recipient string, amount string, startAt uint64, cliffAt uint64, releaseAt uint64, revocable bool) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to create vesting"
+		logger.Error(response.Message)
+		return response, generateError(403, "VSCH001", response.Message)
+	}
+	bigAmount, ok := parseAmount(ctx, amount, BUSY_COIN_SYMBOL)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "VSCH002", response.Message)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VSCH003", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "VSCH004", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if startAt < uint64(now.Seconds) || releaseAt < startAt {
+		response.Message = "Start time of vesting has to be in the future and release time can not be before it"
+		logger.Error(response.Message)
+		return response, generateError(412, "VSCH005", response.Message)
+	}
+	if cliffAt != 0 && (cliffAt < startAt || cliffAt > releaseAt) {
+		response.Message = "Cliff of vesting has to be between its start and release time"
+		logger.Error(response.Message)
+		return response, generateError(412, "VSCH006", response.Message)
+	}
+	adminAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VSCH007", response.Message)
+	}
+	balance, _ := getBalanceHelper(ctx, adminAddress, BUSY_COIN_SYMBOL)
+	if balance.Cmp(bigAmount) == -1 {
+		response.Message = "There is not enough balance in the wallet"
+		logger.Error(response.Message)
+		return response, generateError(402, "VSCH008", response.Message)
+	}
+
+	lockedToken := LockedTokens{
+		DocType:        "lockedToken",
+		ID:             response.TxID,
+		Address:        recipient,
+		Grantor:        adminAddress,
+		TotalAmount:    bigAmount.String(),
+		ReleasedAmount: "0",
+		StartedAt:      startAt,
+		CliffAt:        cliffAt,
+		ReleaseAt:      releaseAt,
+		Revocable:      revocable,
+	}
+	err = putVestingSchedule(ctx, &lockedToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VSCH009", response.Message)
+	}
+	err = chargeTxFee(ctx, adminAddress, "vesting")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "VSCH010", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "vesting")
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: recipient,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+		},
+		TransactionFee: txFee,
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+	response.Message = "Vesting has been scheduled successfully"
+	response.Success = true
+	response.Data = lockedToken
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CreateTokenVesting lock amount of BUSY20 token from default wallet of its admin for recipient and release it
+// linearly between startAt and releaseAt. Tokens are escrowed right away, so unlocking mints nothing
+func (bt *Busy) CreateTokenVesting(ctx contractapi.TransactionContextInterface, recipient string, token string, amount string, startAt uint64, cliffAt uint64, releaseAt uint64, revocable bool) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	if strings.ToUpper(token) == BUSY_COIN_SYMBOL {
+		response.Message = fmt.Sprintf("Use CreateVestingSchedule to vest %s", BUSY_COIN_SYMBOL)
+		logger.Error(response.Message)
+		return response, generateError(412, "TVST001", response.Message)
+	}
+	busy20Token, err := getBusy20Token(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "TVST002", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	adminAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TVST003", response.Message)
+	}
+	if busy20Token.Admin != adminAddress {
+		response.Message = fmt.Sprintf("Only admin of %s can create vesting", busy20Token.TokenSymbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "TVST004", response.Message)
+	}
+	bigAmount, ok := parseAmount(ctx, amount, busy20Token.TokenSymbol)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "TVST005", response.Message)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TVST006", response.Message)
+	}
+	if walletAsBytes == nil {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "TVST007", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if startAt < uint64(now.Seconds) || releaseAt < startAt {
+		response.Message = "Start time of vesting has to be in the future and release time can not be before it"
+		logger.Error(response.Message)
+		return response, generateError(412, "TVST008", response.Message)
+	}
+	if cliffAt != 0 && (cliffAt < startAt || cliffAt > releaseAt) {
+		response.Message = "Cliff of vesting has to be between its start and release time"
+		logger.Error(response.Message)
+		return response, generateError(412, "TVST009", response.Message)
+	}
+
+	lockedToken := LockedTokens{
+		DocType:        "lockedToken",
+		ID:             response.TxID,
+		Address:        recipient,
+		Grantor:        adminAddress,
+		Token:          busy20Token.TokenSymbol,
+		Escrow:         getVestingEscrowAddress(response.TxID),
+		TotalAmount:    bigAmount.String(),
+		ReleasedAmount: "0",
+		StartedAt:      startAt,
+		CliffAt:        cliffAt,
+		ReleaseAt:      releaseAt,
+		Revocable:      revocable,
+	}
+	txFee, _ := getTxFee(ctx, "tokenVesting")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	err = transferHelper(ctx, adminAddress, lockedToken.Escrow, bigAmount, lockedToken.Token, bigTxFee, "tokenVesting")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while escrowing vested tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "TVST010", response.Message)
+	}
+	err = routeFee(ctx, "tokenVesting", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TVST011", response.Message)
+	}
+	err = putVestingSchedule(ctx, &lockedToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TVST012", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses: []UserAddress{
+			{
+				Address: adminAddress,
+				Token:   BUSY_COIN_SYMBOL,
+			},
+			{
+				Address: adminAddress,
+				Token:   lockedToken.Token,
+			},
+			{
+				Address: recipient,
+				Token:   lockedToken.Token,
+			},
+		},
+		TransactionFee: bigTxFee.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+	response.Message = "Vesting has been scheduled successfully"
+	response.Success = true
+	response.Data = lockedToken
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RevokeVesting stop revocable vesting schedule, part vested so far can still be unlocked by recipient.
+// BUSY vestings are revoked by network admin and their unvested part is never minted, BUSY20 vestings
+// are revoked by their grantor and get the unvested part back from escrow
+func (bt *Busy) RevokeVesting(ctx contractapi.TransactionContextInterface, address string, scheduleId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	lockedToken, err := getVestingSchedule(ctx, address, scheduleId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(500, "RVST002", response.Message)
+	}
+	if lockedToken == nil {
+		response.Message = fmt.Sprintf("Vesting schedule %s does not exist for wallet %s", scheduleId, address)
+		logger.Error(response.Message)
+		return response, generateError(404, "RVST003", response.Message)
+	}
+	if lockedToken.Escrow != "" {
+		err = CheckCredentials(ctx, DEFAULT_CREDS, "true")
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(403, "ATU001", response.Message)
+		}
+	}
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	callerAddress, _ := getDefaultWalletAddress(ctx, commonName)
+	if (lockedToken.Escrow == "" && (mspid != "BusyMSP" || commonName != "busy_network")) || (lockedToken.Escrow != "" && callerAddress != lockedToken.Grantor) {
+		response.Message = "You are not allowed to revoke vesting"
+		logger.Error(response.Message)
+		return response, generateError(403, "RVST001", response.Message)
+	}
+	if !lockedToken.Revocable || lockedToken.Revoked {
+		response.Message = fmt.Sprintf("Vesting schedule %s is not revocable or has already been revoked", scheduleId)
+		logger.Error(response.Message)
+		return response, generateError(409, "RVST004", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	vestedAmount := getVestedAmount(lockedToken, uint64(now.Seconds))
+	releasedAmount, _ := new(big.Int).SetString(lockedToken.ReleasedAmount, 10)
+	if releasedAmount.Cmp(vestedAmount) == 1 {
+		vestedAmount = releasedAmount
+	}
+	unvestedAmount, _ := new(big.Int).SetString(lockedToken.TotalAmount, 10)
+	unvestedAmount.Sub(unvestedAmount, vestedAmount)
+	if unvestedAmount.Cmp(bigZero) != 1 {
+		response.Message = fmt.Sprintf("Vesting schedule %s has already fully vested", scheduleId)
+		logger.Error(response.Message)
+		return response, generateError(409, "RVST005", response.Message)
+	}
+	vestingToken := getVestingToken(lockedToken)
+	userAddresses := []UserAddress{{Address: address, Token: vestingToken}}
+	// BUSY vestings are minted on unlock so there is nothing to return to the grantor
+	if lockedToken.Escrow != "" {
+		err = transferHelper(ctx, lockedToken.Escrow, lockedToken.Grantor, unvestedAmount, vestingToken, bigZero, "revokeVesting")
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while returning unvested tokens: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "RVST006", response.Message)
+		}
+		userAddresses = append(userAddresses, UserAddress{Address: lockedToken.Grantor, Token: vestingToken})
+	}
+	lockedToken.Revoked = true
+	lockedToken.RevokedAmount = unvestedAmount.String()
+	lockedToken.RevokedAt = uint64(now.Seconds)
+	err = putVestingSchedule(ctx, lockedToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating vesting schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RVST007", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  userAddresses,
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+	response.Message = fmt.Sprintf("Vesting schedule %s has been revoked, %s will not vest", scheduleId, unvestedAmount.String())
+	response.Success = true
+	response.Data = lockedToken
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetLockedTokens get all vesting schedules of wallet address
+func (bt *Busy) GetLockedTokens(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	schedules, err := getVestingSchedules(ctx, address)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while getting vesting details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GLOK001", response.Message)
+	}
+	if len(schedules) == 0 {
+		response.Message = fmt.Sprintf("Vesting entry does not exist for wallet %s", address)
+		logger.Error(response.Message)
+		return response, generateError(404, "GLOK002", response.Message)
+	}
+
+	response.Message = "Vesting has been successfully fetched"
+	logger.Info(response.Message)
+	response.Data = schedules
+	response.Success = true
+	return response, nil
+}
+
+// AttemptUnlock release everything vested so far across all vesting schedules of default wallet of invoker
+func (bt *Busy) AttemptUnlock(ctx contractapi.TransactionContextInterface) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	commonName, _ := getCommonName(ctx)
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	walletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AULK001", response.Message)
+	}
+	err = CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	fee, _ := getTxFee(ctx, "unlock")
+	bigFee, _ := new(big.Int).SetString(fee, 10)
+
+	balance := getFeePayerBalance(ctx, walletAddress, bigFee, "unlock", BUSY_COIN_SYMBOL)
+	if bigFee.Cmp(balance) == 1 {
+		response.Message = "There is not enough balance for tx fee in the wallet"
+		logger.Error(response.Message)
+		return response, generateError(402, "AULK002", response.Message)
+	}
+
+	schedules, err := getVestingSchedules(ctx, walletAddress)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while getting vesting details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AULK003", response.Message)
+	}
+	if len(schedules) == 0 {
+		response.Message = fmt.Sprintf("Vesting entry does not exist for %s", walletAddress)
+		logger.Error(response.Message)
+		return response, generateError(404, "AULK004", response.Message)
+	}
+	err = autoCompactUTXOs(ctx, walletAddress, BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AULK011", response.Message)
+	}
+
+	// minted BUSY is released in a single utxo, funded vestings are paid out of their own escrow addresses
+	releasableAmount := new(big.Int).Set(bigZero)
+	pending, started, released := false, false, false
+	userAddresses := []UserAddress{{Address: walletAddress, Token: BUSY_COIN_SYMBOL}}
+	for _, lockedToken := range schedules {
+		vestedAmount := getVestedAmount(lockedToken, uint64(now.Seconds))
+		releasedAmount, _ := new(big.Int).SetString(lockedToken.ReleasedAmount, 10)
+		lockedAmount, _ := new(big.Int).SetString(lockedToken.TotalAmount, 10)
+		lockedAmount.Sub(lockedAmount, releasedAmount)
+		if revokedAmount, ok := new(big.Int).SetString(lockedToken.RevokedAmount, 10); ok {
+			lockedAmount.Sub(lockedAmount, revokedAmount)
+		}
+		if lockedAmount.Cmp(bigZero) != 1 {
+			continue
+		}
+		pending = true
+		if uint64(now.Seconds) < lockedToken.StartedAt || uint64(now.Seconds) < lockedToken.CliffAt {
+			continue
+		}
+		started = true
+		if vestedAmount.Cmp(releasedAmount) != 1 || checkTokenNotPaused(ctx, getVestingToken(lockedToken)) != nil {
+			continue
+		}
+		amount := new(big.Int).Sub(vestedAmount, releasedAmount)
+		if lockedToken.Escrow == "" {
+			releasableAmount.Add(releasableAmount, amount)
+		} else {
+			err = transferHelper(ctx, lockedToken.Escrow, walletAddress, amount, lockedToken.Token, bigZero, "unlock")
+			if err != nil {
+				response.Message = fmt.Sprintf("Error occurred while claiming: %s", err.Error())
+				logger.Error(response.Message)
+				return response, generateError(500, "AULK007", response.Message)
+			}
+			userAddresses = append(userAddresses, UserAddress{Address: walletAddress, Token: lockedToken.Token})
+		}
+		released = true
+		lockedToken.ReleasedAmount = vestedAmount.String()
+		err = putVestingSchedule(ctx, lockedToken)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while updating vesting schedule: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "AULK008", response.Message)
+		}
+	}
+	if !pending {
+		response.Message = "There are no funds to claim from the vesting"
+		logger.Error(response.Message)
+		return response, generateError(400, "AULK006", response.Message)
+	}
+	if !started {
+		response.Message = "Vesting has not started yet"
+		logger.Info(response.Message)
+		return response, generateError(425, "AULK005", response.Message)
+	}
+	if !released {
+		response.Message = "There is nothing to release at this time"
+		logger.Error(response.Message)
+		return response, generateError(425, "AULK010", response.Message)
+	}
+	if releasableAmount.Cmp(bigZero) == 1 {
+		err = addUTXO(ctx, walletAddress, releasableAmount, BUSY_COIN_SYMBOL)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while claiming: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "AULK007", response.Message)
+		}
+	}
+
+	err = chargeTxFee(ctx, walletAddress, "unlock", BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "AULK009", response.Message)
+	}
+	balanceData := BalanceEvent{
+		UserAddresses:  userAddresses,
+		TransactionFee: fee,
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = "Vested tokens have been successfully unlocked"
+	response.Success = true
+	response.Data = schedules
+	logger.Info(response.Message)
+	return response, nil
+}
+
+func (bt *Busy) UpdateTransferFee(ctx contractapi.TransactionContextInterface, newTransferFee string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	if mspid != "BusyMSP" {
+		response.Message = "You are not allowed to set the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(403, "UTRF001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if commonName != "busy_network" {
+		response.Message = "You are not allowed to set the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(403, "UTRF001", response.Message)
+	}
+
+	_, err := updateFeeScheduleEntry(ctx, "transfer", newTransferFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating transfer fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UTRF002", response.Message)
+	}
+
+	sender, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, sender)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UTRF003", response.Message)
+	}
+	err = chargeTxFee(ctx, defaultWalletAddress, "transfer")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UTRF004", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = "Transfer fee has been successfully updated"
+	response.Success = true
+	response.Data = newTransferFee
+	logger.Error(response.Message)
+	return response, nil
+}
+
+// GetFeeSchedule get transaction fee of every operation type
+func (bt *Busy) GetFeeSchedule(ctx contractapi.TransactionContextInterface) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	feeSchedule, err := getFeeSchedule(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching fee schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GFSC001", response.Message)
+	}
+
+	response.Message = "Fee schedule has been successfully fetched"
+	response.Success = true
+	response.Data = feeSchedule
+	return response, nil
+}
+
+// UpdateFeeSchedule set transaction fee of operation type, use "default" for operations without their own entry
+func (bt *Busy) UpdateFeeSchedule(ctx contractapi.TransactionContextInterface, txType string, fee string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update the fee schedule"
+		logger.Error(response.Message)
+		return response, generateError(403, "UFSC001", response.Message)
+	}
+	if txType == "" {
+		response.Message = "Operation type can not be empty"
+		logger.Error(response.Message)
+		return response, generateError(400, "UFSC002", response.Message)
+	}
+
+	feeSchedule, err := updateFeeScheduleEntry(ctx, txType, fee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating fee schedule: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UFSC003", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Fee of %s has been successfully updated", txType)
+	response.Success = true
+	response.Data = feeSchedule
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// updateFeeScheduleEntry validate and store fee of a single operation type
+func updateFeeScheduleEntry(ctx contractapi.TransactionContextInterface, txType string, fee string) (*FeeSchedule, error) {
+	bigFee, ok := new(big.Int).SetString(fee, 10)
+	if !ok || bigFee.Cmp(bigZero) == -1 {
+		return nil, fmt.Errorf("invalid fee %s", fee)
+	}
+	feeSchedule, err := getFeeSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeSchedule.Fees[txType] = bigFee.String()
+	feeScheduleAsBytes, _ := json.Marshal(feeSchedule)
+	err = ctx.GetStub().PutState(FEE_SCHEDULE_KEY, feeScheduleAsBytes)
+	if err != nil {
+		return nil, err
+	}
+	return feeSchedule, nil
+}
+
+// GetFeeRoutingConfig get destination of fees of every operation type
+func (bt *Busy) GetFeeRoutingConfig(ctx contractapi.TransactionContextInterface) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	config, err := getFeeRoutingConfig(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching fee routing config: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GFRC001", response.Message)
+	}
+
+	response.Message = "Fee routing config has been successfully fetched"
+	response.Success = true
+	response.Data = config
+	return response, nil
+}
+
+// UpdateFeeRoute set destination of fees of operation type, use "default" for operations without their own route
+func (bt *Busy) UpdateFeeRoute(ctx contractapi.TransactionContextInterface, txType string, destination string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update the fee routing"
+		logger.Error(response.Message)
+		return response, generateError(403, "UFRT001", response.Message)
+	}
+	if txType == "" {
+		response.Message = "Operation type can not be empty"
+		logger.Error(response.Message)
+		return response, generateError(400, "UFRT002", response.Message)
+	}
+	if destination != FEE_DESTINATION_BURN && destination != FEE_DESTINATION_TREASURY && destination != FEE_DESTINATION_STAKERS {
+		response.Message = fmt.Sprintf("Invalid destination, please select destination from [%s, %s, %s]", FEE_DESTINATION_BURN, FEE_DESTINATION_TREASURY, FEE_DESTINATION_STAKERS)
+		logger.Error(response.Message)
+		return response, generateError(412, "UFRT003", response.Message)
+	}
+
+	config, err := getFeeRoutingConfig(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching fee routing config: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UFRT004", response.Message)
+	}
+	if destination == FEE_DESTINATION_TREASURY && config.Treasury == "" {
+		response.Message = "Treasury wallet has not been set, please set it with UpdateFeeTreasury first"
+		logger.Error(response.Message)
+		return response, generateError(412, "UFRT005", response.Message)
+	}
+	if txType == DEFAULT_FEE_TYPE {
+		config.Default = destination
+	} else {
+		config.Routes[txType] = destination
+	}
+	configAsBytes, _ := json.Marshal(config)
+	err = ctx.GetStub().PutState(FEE_ROUTING_CONFIG, configAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating fee routing config: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UFRT006", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
 	balanceAsBytes, _ := json.Marshal(balanceData)
 	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
 	if err != nil {
@@ -1357,14 +4175,16 @@ func (bt *Busy) MultibeneficiaryVestingV1(ctx contractapi.TransactionContextInte
 		logger.Error(response.Message)
 		return response, generateError(500, "BAL001", response.Message)
 	}
-	response.Message = "Vesting has been scheduled successfully"
-	logger.Info(response.Message)
+
+	response.Message = fmt.Sprintf("Fees of %s will be routed to %s", txType, destination)
 	response.Success = true
+	response.Data = config
+	logger.Info(response.Message)
 	return response, nil
 }
 
-// MultibeneficiaryVestingV2 vesting v2
-func (bt *Busy) MultibeneficiaryVestingV2(ctx contractapi.TransactionContextInterface, recipient string, amount string, startAt uint64, releaseAt uint64) (*Response, error) {
+// UpdateFeeTreasury set wallet receiving fees routed to treasury
+func (bt *Busy) UpdateFeeTreasury(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1372,115 +4192,49 @@ func (bt *Busy) MultibeneficiaryVestingV2(ctx contractapi.TransactionContextInte
 		Data:    nil,
 	}
 
-	if amount == "0" {
-		response.Message = "Zero amount can not be vested"
-		logger.Error(response.Message)
-		return response, generateError(412, "VTWO001", response.Message)
-	}
-
-	// check if wallet already exists
-	walletAsBytes, err := ctx.GetStub().GetState(recipient)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "VTWO002", response.Message)
-	}
-	if walletAsBytes == nil {
-		response.Message = fmt.Sprintf("Wallet %s does not exist", recipient)
-		logger.Error(response.Message)
-		return response, generateError(404, "VTWO003", response.Message)
-	}
-
-	now, _ := ctx.GetStub().GetTxTimestamp()
 	mspid, _ := ctx.GetClientIdentity().GetMSPID()
-	if mspid != "BusyMSP" {
-		response.Message = "You are not allowed to create vesting"
-		logger.Error(response.Message)
-		return response, generateError(403, "VTWO004", response.Message)
-	}
 	commonName, _ := getCommonName(ctx)
-	if commonName != "busy_network" {
-		response.Message = "You are not allowed to create vesting"
-		logger.Error(response.Message)
-		return response, generateError(404, "VTWO004", response.Message)
-	}
-	bigAmount, _ := new(big.Int).SetString(amount, 10)
-	if bigAmount.Cmp(bigZero) == 0 {
-		response.Message = "Zero amount can not be vested"
-		logger.Error(response.Message)
-		return response, generateError(412, "VTWO001", response.Message)
-	}
-	adminAddress, _ := getDefaultWalletAddress(ctx, commonName)
-	balance, _ := getBalanceHelper(ctx, adminAddress, BUSY_COIN_SYMBOL)
-	if balance.Cmp(bigAmount) == -1 {
-		response.Message = "There is not enough balance in the wallet"
-		logger.Error(response.Message)
-		return response, generateError(402, "VTWO005", response.Message)
-	}
-	if releaseAt < startAt {
-		response.Message = "Release time of vesting has to be greater then start time"
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update the fee treasury"
 		logger.Error(response.Message)
-		return response, generateError(412, "VTWO006", response.Message)
+		return response, generateError(403, "UFTR001", response.Message)
 	}
-
-	lockedTokenAsBytes, _ := ctx.GetStub().GetState(fmt.Sprintf("vesting~%s", recipient))
-	if lockedTokenAsBytes != nil {
-		response.Message = fmt.Sprintf("Vesting for wallet %s already exists", recipient)
+	walletAsBytes, err := ctx.GetStub().GetState(address)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(409, "VTWO007", response.Message)
+		return response, generateError(500, "UFTR002", response.Message)
 	}
-	if releaseAt < uint64(now.Seconds) {
-		response.Message = "Release time of vesting has to be in the future"
-		logger.Error(response.Message)
-		return response, generateError(412, "VTWO008", response.Message)
+	wallet := Wallet{}
+	if walletAsBytes != nil {
+		_ = json.Unmarshal(walletAsBytes, &wallet)
 	}
-
-	if startAt < uint64(now.Seconds) {
-		response.Message = "Start time of vesting has to be in the future"
+	if wallet.DocType != "wallet" {
+		response.Message = fmt.Sprintf("Wallet %s does not exist", address)
 		logger.Error(response.Message)
-		return response, generateError(412, "VTWO009", response.Message)
+		return response, generateError(404, "UFTR003", response.Message)
 	}
 
-	totalAmount := new(big.Int).Set(bigAmount)
-	lockedToken := LockedTokens{
-		DocType:        "lockedToken",
-		TotalAmount:    totalAmount.String(),
-		ReleasedAmount: "0",
-		StartedAt:      startAt,
-		ReleaseAt:      releaseAt,
-	}
-	lockedTokenAsBytes, _ = json.Marshal(lockedToken)
-	err = ctx.GetStub().PutState(fmt.Sprintf("vesting~%s", recipient), lockedTokenAsBytes)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while adding vesting schedule: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "VTWO010", response.Message)
-	}
-	sender, _ := getCommonName(ctx)
-	defaultWalletAddress, err := getDefaultWalletAddress(ctx, sender)
+	config, err := getFeeRoutingConfig(ctx)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while fetching fee routing config: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VTWO011", response.Message)
+		return response, generateError(500, "UFTR004", response.Message)
 	}
-	err = burnTxFeeWithTotalSupply(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	config.Treasury = address
+	configAsBytes, _ := json.Marshal(config)
+	err = ctx.GetStub().PutState(FEE_ROUTING_CONFIG, configAsBytes)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while updating fee routing config: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VTWO012", response.Message)
+		return response, generateError(500, "UFTR005", response.Message)
 	}
-	txFee, _ := getCurrentTxFee(ctx)
+
 	balanceData := BalanceEvent{
-		UserAddresses: []UserAddress{
-			{
-				Address: recipient,
-				Token:   BUSY_COIN_SYMBOL,
-			},
-		},
-		TransactionFee: txFee,
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
 		TransactionId:  response.TxID,
 	}
-
 	balanceAsBytes, _ := json.Marshal(balanceData)
 	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
 	if err != nil {
@@ -1488,14 +4242,16 @@ func (bt *Busy) MultibeneficiaryVestingV2(ctx contractapi.TransactionContextInte
 		logger.Error(response.Message)
 		return response, generateError(500, "BAL001", response.Message)
 	}
-	response.Message = "Vesting has been scheduled successfully"
-	logger.Info(response.Message)
+
+	response.Message = fmt.Sprintf("Treasury wallet has been successfully set to %s", address)
 	response.Success = true
+	response.Data = config
+	logger.Info(response.Message)
 	return response, nil
 }
 
-// GetLockedTokens get entry of vesting schedule for wallet address
-func (bt *Busy) GetLockedTokens(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+// GetCollectedFees get fees routed to every destination per day, periods are UTC days in YYYY-MM-DD format
+func (bt *Busy) GetCollectedFees(ctx contractapi.TransactionContextInterface, fromPeriod string, toPeriod string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1503,29 +4259,73 @@ func (bt *Busy) GetLockedTokens(ctx contractapi.TransactionContextInterface, add
 		Data:    nil,
 	}
 
-	lockedTokenAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("vesting~%s", address))
+	from, err := time.Parse(FEE_PERIOD_LAYOUT, fromPeriod)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while getting vesting details: %s", err.Error())
+		response.Message = fmt.Sprintf("Invalid period %s, expected format is YYYY-MM-DD", fromPeriod)
 		logger.Error(response.Message)
-		return response, generateError(500, "GLOK001", response.Message)
+		return response, generateError(400, "GCFE001", response.Message)
 	}
-	if lockedTokenAsBytes == nil {
-		response.Message = fmt.Sprintf("Vesting entry does not exist for wallet %s", address)
+	to, err := time.Parse(FEE_PERIOD_LAYOUT, toPeriod)
+	if err != nil {
+		response.Message = fmt.Sprintf("Invalid period %s, expected format is YYYY-MM-DD", toPeriod)
 		logger.Error(response.Message)
-		return response, generateError(404, "GLOK002", response.Message)
+		return response, generateError(400, "GCFE001", response.Message)
+	}
+	if to.Before(from) || to.Sub(from) > MAX_FEE_PERIOD_DAYS*24*time.Hour {
+		response.Message = fmt.Sprintf("Period range must be in order and not longer than %d days", MAX_FEE_PERIOD_DAYS)
+		logger.Error(response.Message)
+		return response, generateError(412, "GCFE002", response.Message)
 	}
-	var lockedToken LockedTokens
-	_ = json.Unmarshal(lockedTokenAsBytes, &lockedToken)
 
-	response.Message = "Vesting has been successfully fetched"
-	logger.Info(response.Message)
-	response.Data = lockedToken
+	collections := []FeeCollection{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		period := day.Format(FEE_PERIOD_LAYOUT)
+		resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(feeAccountingPrefix, []string{period})
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching collected fees: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "GCFE003", response.Message)
+		}
+		// keys are ordered by destination inside a period so equal destinations are next to each other
+		var current *FeeCollection
+		for resultIterator.HasNext() {
+			data, err := resultIterator.Next()
+			if err != nil {
+				resultIterator.Close()
+				response.Message = fmt.Sprintf("Error occurred while fetching collected fees: %s", err.Error())
+				logger.Error(response.Message)
+				return response, generateError(500, "GCFE003", response.Message)
+			}
+			_, attributes, _ := ctx.GetStub().SplitCompositeKey(data.Key)
+			destination := attributes[1]
+			if current == nil || current.Destination != destination {
+				if current != nil {
+					collections = append(collections, *current)
+				}
+				current = &FeeCollection{Period: period, Destination: destination, Amount: "0"}
+			}
+			amount, _ := new(big.Int).SetString(current.Amount, 10)
+			fee, _ := new(big.Int).SetString(string(data.Value), 10)
+			current.Amount = amount.Add(amount, fee).String()
+		}
+		resultIterator.Close()
+		if current != nil {
+			collections = append(collections, *current)
+		}
+	}
+
+	response.Message = "Collected fees have been successfully fetched"
 	response.Success = true
+	response.Data = collections
 	return response, nil
 }
 
-// AttemptUnlock
-func (bt *Busy) AttemptUnlock(ctx contractapi.TransactionContextInterface) (*Response, error) {
+// DistributeStakerFees split fees routed to stakers among active staking addresses by staked coins,
+// every share is credited to default wallet of the staking address. Staking addresses are walked in
+// pages of pageSize keys, call again until the distribution is complete. A distribution first sums staked
+// coins of all staking addresses and then pays the pool balance it started with, fees routed meanwhile
+// are left for the next distribution
+func (bt *Busy) DistributeStakerFees(ctx contractapi.TransactionContextInterface, pageSize uint64) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1533,150 +4333,168 @@ func (bt *Busy) AttemptUnlock(ctx contractapi.TransactionContextInterface) (*Res
 		Data:    nil,
 	}
 
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
 	commonName, _ := getCommonName(ctx)
-	now, _ := ctx.GetStub().GetTxTimestamp()
-	walletAddress, err := getDefaultWalletAddress(ctx, commonName)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to distribute staker fees"
 		logger.Error(response.Message)
-		return response, generateError(500, "AULK001", response.Message)
+		return response, generateError(403, "DSTF001", response.Message)
 	}
-	err = CheckCredentials(ctx, DEFAULT_CREDS, "true")
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+	if pageSize == 0 {
+		response.Message = "Page size has to be greater than zero"
 		logger.Error(response.Message)
-		return response, generateError(403, "ATU001", response.Message)
+		return response, generateError(412, "DSTF002", response.Message)
 	}
-	fee, _ := getCurrentTxFee(ctx)
-	bigFee, _ := new(big.Int).SetString(fee, 10)
 
-	balance, _ := getBalanceHelper(ctx, walletAddress, BUSY_COIN_SYMBOL)
-	if bigFee.Cmp(balance) == 1 {
-		response.Message = "There is not enough balance for tx fee in the wallet"
+	distribution, err := getStakerFeeDistribution(ctx)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching staker fee distribution: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(402, "AULK002", response.Message)
+		return response, generateError(500, "DSTF003", response.Message)
+	}
+	if distribution == nil {
+		poolBalance, _, err := pruneUTXOs(ctx, STAKER_FEE_POOL_KEY, BUSY_COIN_SYMBOL)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching staker fee pool: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "DSTF004", response.Message)
+		}
+		if poolBalance.Cmp(bigZero) != 1 {
+			response.Message = "There are no staker fees to distribute"
+			logger.Error(response.Message)
+			return response, generateError(409, "DSTF005", response.Message)
+		}
+		now, _ := ctx.GetStub().GetTxTimestamp()
+		distribution = &StakerFeeDistribution{
+			DocType:     "stakerFeeDistribution",
+			Pass:        DISTRIBUTION_PASS_COUNT,
+			PoolAmount:  poolBalance.String(),
+			TotalStaked: bigZero.String(),
+			Distributed: bigZero.String(),
+			NextKey:     STAKING_ADDRESS_START_KEY,
+			StartedAt:   uint64(now.Seconds),
+		}
 	}
+	poolAmount, _ := new(big.Int).SetString(distribution.PoolAmount, 10)
+	totalStaked, _ := new(big.Int).SetString(distribution.TotalStaked, 10)
+	distributed, _ := new(big.Int).SetString(distribution.Distributed, 10)
 
-	lockedTokenAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("vesting~%s", walletAddress))
+	// key range is re-validated at commit unlike a rich query
+	resultIterator, err := ctx.GetStub().GetStateByRange(distribution.NextKey, STAKING_ADDRESS_END_KEY)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while getting vesting details: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "AULK003", response.Message)
-	}
-	if lockedTokenAsBytes == nil {
-		response.Message = fmt.Sprintf("Vesting entry does not exist for %s", walletAddress)
+		response.Message = fmt.Sprintf("Error occurred while fetching staking addresses: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(404, "AULK004", response.Message)
+		return response, generateError(500, "DSTF006", response.Message)
 	}
-	var lockedToken LockedTokens
-	_ = json.Unmarshal(lockedTokenAsBytes, &lockedToken)
-	bigTotalAmount, _ := new(big.Int).SetString(lockedToken.TotalAmount, 10)
-	bigReleasedAmount, _ := new(big.Int).SetString(lockedToken.ReleasedAmount, 10)
-	bigStartedAt := new(big.Int).SetUint64(lockedToken.StartedAt)
-	bigReleasedAt := new(big.Int).SetUint64(lockedToken.ReleaseAt)
-	bigNow := new(big.Int).SetUint64(uint64(now.Seconds))
+	defer resultIterator.Close()
 
-	if lockedToken.StartedAt > uint64(now.Seconds) {
-		response.Message = "Vesting has not started yet"
-		logger.Info(response.Message)
-		return response, generateError(425, "AULK005", response.Message)
-	}
-	if lockedToken.ReleaseAt <= uint64(now.Seconds) {
-		if lockedToken.TotalAmount == lockedToken.ReleasedAmount {
-			response.Message = "There are no funds to claim from the vesting"
-			response.Success = false
-			logger.Error(response.Message)
-			return response, generateError(400, "AULK006", response.Message)
-		}
-		amountToReleaseNow := bigTotalAmount.Sub(bigTotalAmount, bigReleasedAmount)
-		lockedToken.ReleasedAmount = lockedToken.TotalAmount
-		err = addUTXO(ctx, walletAddress, amountToReleaseNow, BUSY_COIN_SYMBOL)
+	var scanned uint64
+	nextKey := ""
+	paid := new(big.Int).Set(bigZero)
+	shares := map[string]string{}
+	userAddresses := []UserAddress{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
 		if err != nil {
-			response.Message = fmt.Sprintf("Error occurred while claiming: %s", err.Error())
+			response.Message = fmt.Sprintf("Error occurred while fetching staking addresses: %s", err.Error())
 			logger.Error(response.Message)
-			return response, generateError(500, "AULK007", response.Message)
+			return response, generateError(500, "DSTF006", response.Message)
+		}
+		if scanned == pageSize {
+			nextKey = data.Key
+			break
+		}
+		scanned++
+
+		stakingAddr := Wallet{}
+		if json.Unmarshal(data.Value, &stakingAddr) != nil || stakingAddr.DocType != "stakingAddr" {
+			continue
 		}
-		lockedTokenAsBytes, _ := json.Marshal(lockedToken)
-		err = ctx.GetStub().PutState(fmt.Sprintf("vesting~%s", walletAddress), lockedTokenAsBytes)
+		stakingInfo, err := getStakingInfo(ctx, stakingAddr.Address)
 		if err != nil {
-			response.Message = fmt.Sprintf("Error occurred while updating vesting schedule: %s", err.Error())
+			response.Message = fmt.Sprintf("Error occurred while fetching staking info: %s", err.Error())
 			logger.Error(response.Message)
-			return response, generateError(500, "AULK008", response.Message)
+			return response, generateError(500, "DSTF007", response.Message)
+		}
+		stakedCoins, ok := new(big.Int).SetString(stakingInfo.StakedCoins, 10)
+		if stakingInfo.Unstaked || !ok || stakedCoins.Cmp(bigZero) != 1 {
+			continue
+		}
+		if distribution.Pass == DISTRIBUTION_PASS_COUNT {
+			totalStaked = totalStaked.Add(totalStaked, stakedCoins)
+			continue
 		}
 
-		// Burning the tx fee in attempt Unlock.
-		err = burnTxFeeWithTotalSupply(ctx, walletAddress, BUSY_COIN_SYMBOL)
+		if stakingInfo.DefaultWalletAddress == "" {
+			stakingInfo.DefaultWalletAddress, _ = getDefaultWalletAddress(ctx, stakingAddr.UserID)
+		}
+		// frozen wallets can not receive funds, their share stays in the pool
+		if stakingInfo.DefaultWalletAddress == "" || checkWalletNotFrozen(ctx, stakingInfo.DefaultWalletAddress) != nil {
+			continue
+		}
+		// staked coins may have grown since they were counted, pool can never pay out more than it had
+		share := new(big.Int).Mul(poolAmount, stakedCoins)
+		share = share.Div(share, totalStaked)
+		left := new(big.Int).Sub(poolAmount, distributed)
+		left = left.Sub(left, paid)
+		if share.Cmp(left) == 1 {
+			share = left
+		}
+		if share.Cmp(bigZero) != 1 {
+			continue
+		}
+		err = putUTXO(ctx, stakingInfo.DefaultWalletAddress, share, BUSY_COIN_SYMBOL, "stakerFee~"+stakingAddr.Address)
+		if err == nil {
+			err = addHistory(ctx, stakingInfo.DefaultWalletAddress, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_IN, stakingAddr.Address, share, bigZero, "stakerFee~"+stakingAddr.Address)
+		}
 		if err != nil {
-			response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+			response.Message = fmt.Sprintf("Error occurred while crediting staker fee: %s", err.Error())
 			logger.Error(response.Message)
-			return response, generateError(500, "AULK009", response.Message)
-		}
-		txFee, _ := getCurrentTxFee(ctx)
-		balanceData := BalanceEvent{
-			UserAddresses: []UserAddress{
-				{
-					Address: walletAddress,
-					Token:   BUSY_COIN_SYMBOL,
-				},
-			},
-			TransactionFee: txFee,
-			TransactionId:  response.TxID,
+			return response, generateError(500, "DSTF008", response.Message)
 		}
-		balanceAsBytes, _ := json.Marshal(balanceData)
-		err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+		paid = paid.Add(paid, share)
+		shares[stakingAddr.Address] = share.String()
+		userAddresses = append(userAddresses, UserAddress{
+			Address: stakingInfo.DefaultWalletAddress,
+			Token:   BUSY_COIN_SYMBOL,
+		})
+	}
+
+	if paid.Cmp(bigZero) == 1 {
+		err = putUTXO(ctx, STAKER_FEE_POOL_KEY, new(big.Int).Mul(paid, minusOne), BUSY_COIN_SYMBOL, "distribute")
 		if err != nil {
-			response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+			response.Message = fmt.Sprintf("Error occurred while updating staker fee pool: %s", err.Error())
 			logger.Error(response.Message)
-			return response, generateError(500, "BAL001", response.Message)
+			return response, generateError(500, "DSTF009", response.Message)
 		}
-		response.Message = "All tokens have been already unlocked"
-		response.Success = true
-		logger.Info(response.Message)
-		return response, nil
-	}
-	releasableAmount := bigTotalAmount.Mul(bigNow.Sub(bigNow, bigStartedAt), bigTotalAmount).Div(bigTotalAmount, bigReleasedAt.Sub(bigReleasedAt, bigStartedAt))
-	if releasableAmount.String() == "0" {
-		response.Message = "There is nothing to release at this time"
-		logger.Error(response.Message)
-		return response, generateError(425, "AULK010", response.Message)
 	}
-	if releasableAmount.Cmp(bigTotalAmount) == 1 {
-		response.Message = "There is nothing to release at this time"
-		logger.Error(response.Message)
-		return response, generateError(425, "AULK010", response.Message)
+	distribution.TotalStaked = totalStaked.String()
+	distribution.Distributed = distributed.Add(distributed, paid).String()
+	distribution.NextKey = nextKey
+
+	complete := false
+	if nextKey == "" && distribution.Pass == DISTRIBUTION_PASS_COUNT && totalStaked.Cmp(bigZero) == 1 {
+		distribution.Pass = DISTRIBUTION_PASS_PAY
+		distribution.NextKey = STAKING_ADDRESS_START_KEY
+	} else if nextKey == "" {
+		// rounding leftover and shares of frozen wallets stay in the pool for the next distribution
+		complete = true
 	}
-	releasableAmount = releasableAmount.Sub(releasableAmount, bigReleasedAmount)
-	_ = addUTXO(ctx, walletAddress, releasableAmount, BUSY_COIN_SYMBOL)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while claiming: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "AULK007", response.Message)
+	if complete {
+		err = ctx.GetStub().DelState(STAKER_FEE_DISTRIBUTION_KEY)
+	} else {
+		distributionAsBytes, _ := json.Marshal(distribution)
+		err = ctx.GetStub().PutState(STAKER_FEE_DISTRIBUTION_KEY, distributionAsBytes)
 	}
-	bigReleasedAmount = bigReleasedAmount.Add(bigReleasedAmount, releasableAmount)
-	lockedToken.ReleasedAmount = bigReleasedAmount.String()
-	lockedTokenAsBytes, _ = json.Marshal(lockedToken)
-	err = ctx.GetStub().PutState(fmt.Sprintf("vesting~%s", walletAddress), lockedTokenAsBytes)
 	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while updating vesting schedule: %s", err.Error())
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "AULK008", response.Message)
+		return response, generateError(500, "DSTF010", response.Message)
 	}
 
-	err = burnTxFeeWithTotalSupply(ctx, walletAddress, BUSY_COIN_SYMBOL)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "AULK009", response.Message)
-	}
-	txFee, _ := getCurrentTxFee(ctx)
 	balanceData := BalanceEvent{
-		UserAddresses: []UserAddress{
-			{
-				Address: walletAddress,
-				Token:   BUSY_COIN_SYMBOL,
-			},
-		},
-		TransactionFee: txFee,
+		UserAddresses:  userAddresses,
+		TransactionFee: bigZero.String(),
 		TransactionId:  response.TxID,
 	}
 	balanceAsBytes, _ := json.Marshal(balanceData)
@@ -1687,13 +4505,22 @@ func (bt *Busy) AttemptUnlock(ctx contractapi.TransactionContextInterface) (*Res
 		return response, generateError(500, "BAL001", response.Message)
 	}
 
-	response.Message = "All tokens have been already unlocked"
+	response.Message = fmt.Sprintf("%s of staker fees have been distributed among %d staking addresses", paid.String(), len(shares))
+	if complete {
+		response.Message = fmt.Sprintf("Staker fee distribution is complete, %s have been distributed in total", distribution.Distributed)
+	}
 	response.Success = true
+	response.Data = map[string]interface{}{
+		"distribution": distribution,
+		"complete":     complete,
+		"shares":       shares,
+	}
 	logger.Info(response.Message)
 	return response, nil
 }
 
-func (bt *Busy) UpdateTransferFee(ctx contractapi.TransactionContextInterface, newTransferFee string) (*Response, error) {
+// UpdateUTXOCompactionThreshold set number of utxos after which balances are compacted automatically, 0 disables it
+func (bt *Busy) UpdateUTXOCompactionThreshold(ctx contractapi.TransactionContextInterface, threshold uint64) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1702,37 +4529,23 @@ func (bt *Busy) UpdateTransferFee(ctx contractapi.TransactionContextInterface, n
 	}
 
 	mspid, _ := ctx.GetClientIdentity().GetMSPID()
-	if mspid != "BusyMSP" {
-		response.Message = "You are not allowed to set the transaction fee"
-		logger.Error(response.Message)
-		return response, generateError(403, "UTRF001", response.Message)
-	}
 	commonName, _ := getCommonName(ctx)
-	if commonName != "busy_network" {
-		response.Message = "You are not allowed to set the transaction fee"
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to set the utxo compaction threshold"
 		logger.Error(response.Message)
-		return response, generateError(403, "UTRF001", response.Message)
+		return response, generateError(403, "UCTH001", response.Message)
 	}
-
-	err := ctx.GetStub().PutState("transferFees", []byte(newTransferFee))
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while updating transfer fee: %s", err.Error())
+	if threshold == 1 {
+		response.Message = "Compaction threshold has to be 0 or greater than 1"
 		logger.Error(response.Message)
-		return response, generateError(500, "UTRF002", response.Message)
+		return response, generateError(412, "UCTH002", response.Message)
 	}
 
-	sender, _ := getCommonName(ctx)
-	defaultWalletAddress, err := getDefaultWalletAddress(ctx, sender)
-	if err != nil {
-		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "UTRF003", response.Message)
-	}
-	err = burnTxFeeWithTotalSupply(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	err := ctx.GetStub().PutState(UTXO_COMPACTION_THRESHOLD_KEY, []byte(strconv.FormatUint(threshold, 10)))
 	if err != nil {
-		response.Message = fmt.Sprintf("Error while burning transfer fee: %s", err.Error())
+		response.Message = fmt.Sprintf("Error occurred while updating utxo compaction threshold: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "UTRF004", response.Message)
+		return response, generateError(500, "UCTH003", response.Message)
 	}
 
 	balanceData := BalanceEvent{
@@ -1748,10 +4561,10 @@ func (bt *Busy) UpdateTransferFee(ctx contractapi.TransactionContextInterface, n
 		return response, generateError(500, "BAL001", response.Message)
 	}
 
-	response.Message = "Transfer fee has been successfully updated"
+	response.Message = "UTXO compaction threshold has been successfully updated"
 	response.Success = true
-	response.Data = newTransferFee
-	logger.Error(response.Message)
+	response.Data = threshold
+	logger.Info(response.Message)
 	return response, nil
 }
 
@@ -1784,7 +4597,8 @@ func (bt *Busy) GetTokenDetails(ctx contractapi.TransactionContextInterface, tok
 	return response, nil
 }
 
-func (bt *Busy) GetStakingInfo(ctx contractapi.TransactionContextInterface, walletId string) (*Response, error) {
+// GetStakingInfo staking details of a page of staking addresses owned by user of walletId
+func (bt *Busy) GetStakingInfo(ctx contractapi.TransactionContextInterface, walletId string, pageSize int32, bookmark string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -1792,6 +4606,11 @@ func (bt *Busy) GetStakingInfo(ctx contractapi.TransactionContextInterface, wall
 		Data:    nil,
 	}
 
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "SKI007", response.Message)
+	}
 	walletAsBytes, err := ctx.GetStub().GetState(walletId)
 	if walletAsBytes == nil {
 		response.Message = fmt.Sprintf("Wallet %s does not exist", walletId)
@@ -1824,7 +4643,7 @@ func (bt *Busy) GetStakingInfo(ctx contractapi.TransactionContextInterface, wall
 			"docType": "stakingAddr"
 		 } 
 	}`, walletDetails.UserID)
-	resultIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(queryString, pageSize, bookmark)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
 		logger.Error(response.Message)
@@ -1866,7 +4685,11 @@ func (bt *Busy) GetStakingInfo(ctx contractapi.TransactionContextInterface, wall
 
 	response.Message = "Staking details have been successfully fetched"
 	response.Success = true
-	response.Data = responseData
+	response.Data = PagedResult{
+		Records:             responseData,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
 	logger.Info(response.Message)
 	return response, nil
 }
@@ -1880,7 +4703,7 @@ func (bt *Busy) Claim(ctx contractapi.TransactionContextInterface, stakingAddr s
 	}
 
 	commonName, _ := getCommonName(ctx)
-	fee, _ := getCurrentTxFee(ctx)
+	fee, _ := getTxFee(ctx, "claim")
 	bigFee, _ := new(big.Int).SetString(fee, 10)
 	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
@@ -1921,11 +4744,22 @@ func (bt *Busy) Claim(ctx contractapi.TransactionContextInterface, stakingAddr s
 		return response, generateError(500, "CLM005", response.Message)
 	}
 
+	err = autoCompactUTXOs(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLM015", response.Message)
+	}
+
 	response, claimableAmounAfterDeductingFee, err := claimHelper(ctx, stakingAddr, defaultWalletAddress, response, currentPhaseConfig, bigFee)
 	if err != nil {
 		return response, err
 	}
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, claimableAmounAfterDeductingFee)
+	// reward is minted in full, the fee part of it is routed like every other fee
+	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Add(claimableAmounAfterDeductingFee, bigFee))
+	if err == nil {
+		err = routeFee(ctx, "claim", bigFee)
+	}
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
 		logger.Error(response.Message)
@@ -1990,7 +4824,7 @@ func claimHelper(ctx contractapi.TransactionContextInterface, stakingAddr string
 
 	amounOtherThenStakingLimit := bigCurrentStakingAmount.Sub(bigCurrentStakingAmount, bigCurrentStakingLimit)
 	logger.Infof("amounOtherThenStakingLimit: %s", amounOtherThenStakingLimit.String())
-	err = transferHelper(ctx, stakingAddr, defaultWalletAddress, amounOtherThenStakingLimit, BUSY_COIN_SYMBOL, bigZero)
+	err = transferHelper(ctx, stakingAddr, defaultWalletAddress, amounOtherThenStakingLimit, BUSY_COIN_SYMBOL, bigZero, "")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while transferring from staking address to default wallet: %s", err.Error())
 		logger.Error(response.Message)
@@ -2036,7 +4870,7 @@ func (bt *Busy) ClaimAll(ctx contractapi.TransactionContextInterface) (*Response
 	}
 
 	commonName, _ := getCommonName(ctx)
-	fee, _ := getCurrentTxFee(ctx)
+	fee, _ := getTxFee(ctx, "claim")
 	bigFee, _ := new(big.Int).SetString(fee, 10)
 	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
@@ -2051,6 +4885,13 @@ func (bt *Busy) ClaimAll(ctx contractapi.TransactionContextInterface) (*Response
 		return response, generateError(500, "CLM005", response.Message)
 	}
 
+	err = autoCompactUTXOs(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLM015", response.Message)
+	}
+
 	var queryString string = fmt.Sprintf(`{
 		"selector": {
 			"userId": "%s",
@@ -2098,7 +4939,10 @@ func (bt *Busy) ClaimAll(ctx contractapi.TransactionContextInterface) (*Response
 		return response, generateError(409, "CLM014", response.Message)
 	}
 
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, totalClaimAmountAfterFee)
+	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Add(totalClaimAmountAfterFee, totalFee))
+	if err == nil {
+		err = routeFee(ctx, "claim", totalFee)
+	}
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
 		logger.Error(response.Message)
@@ -2116,7 +4960,7 @@ func (bt *Busy) ClaimAll(ctx contractapi.TransactionContextInterface) (*Response
 	return response, nil
 }
 
-func (bt *Busy) FetchStakingAddress(ctx contractapi.TransactionContextInterface) (*Response, error) {
+func (bt *Busy) FetchStakingAddress(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
@@ -2129,13 +4973,18 @@ func (bt *Busy) FetchStakingAddress(ctx contractapi.TransactionContextInterface)
 		logger.Error(response.Message)
 		return response, fmt.Errorf(response.Message)
 	}
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, fmt.Errorf(response.Message)
+	}
 	var queryString string = `{
 		"selector": {
 			"docType": "stakingAddr"
 		 } 
 	}`
 
-	resultIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	resultIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(queryString, pageSize, bookmark)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
 		logger.Error(response.Message)
@@ -2155,7 +5004,11 @@ func (bt *Busy) FetchStakingAddress(ctx contractapi.TransactionContextInterface)
 		tmpData["createdFrom"] = defaultWalletAddress
 		responseData = append(responseData, tmpData)
 	}
-	response.Data = responseData
+	response.Data = PagedResult{
+		Records:             responseData,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
 	response.Success = true
 	response.Message = "Staking Address Successfully fetched"
 	return response, nil
@@ -2170,7 +5023,7 @@ func (bt *Busy) Unstake(ctx contractapi.TransactionContextInterface, stakingAddr
 	}
 
 	commonName, _ := getCommonName(ctx)
-	fee, _ := getCurrentTxFee(ctx)
+	fee, _ := getTxFee(ctx, "unstake")
 	bigFee, _ := new(big.Int).SetString(fee, 10)
 	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
 	if err != nil {
@@ -2233,7 +5086,13 @@ func (bt *Busy) Unstake(ctx contractapi.TransactionContextInterface, stakingAddr
 	bigStakingAmount, _ := new(big.Int).SetString(stakingInfo.StakedCoins, 10)
 	logger.Infof("staking amount for staking address %s is %s it is fetched from staking info", stakingAddr, bigStakingAmount.String())
 	fmt.Println(bigZero)
-	err = transferHelper(ctx, stakingAddr, defaultWalletAddress, bigStakingAmount, BUSY_COIN_SYMBOL, bigZero)
+	err = autoCompactUTXOs(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while compacting utxos: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "USTK014", response.Message)
+	}
+	err = transferHelper(ctx, stakingAddr, defaultWalletAddress, bigStakingAmount, BUSY_COIN_SYMBOL, bigZero, "")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while transferring from staking address to default wallet: %s", err.Error())
 		logger.Error(response.Message)
@@ -2275,7 +5134,10 @@ func (bt *Busy) Unstake(ctx contractapi.TransactionContextInterface, stakingAddr
 		return response, generateError(500, "USTK012", response.Message)
 	}
 
-	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, claimableAmounAfterDeductingFee)
+	err = addTotalSupplyUTXO(ctx, BUSY_COIN_SYMBOL, new(big.Int).Add(claimableAmounAfterDeductingFee, bigFee))
+	if err == nil {
+		err = routeFee(ctx, "unstake", bigFee)
+	}
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while updating total supply: %s", err.Error())
 		logger.Error(response.Message)
@@ -2338,7 +5200,7 @@ func (bt *Busy) GetCurrentFee(ctx contractapi.TransactionContextInterface) (*Res
 	}
 
 	// Fetch current transfer fee
-	transferFee, err := getCurrentTxFee(ctx)
+	transferFee, err := getTxFee(ctx, "transfer")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error occurred while fetching transfer fee %s", err.Error())
 		logger.Error(response.Message)
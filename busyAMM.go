@@ -0,0 +1,865 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const POOL_EVENT = "POOL"
+
+const (
+	POOL_ACTION_CREATE = "create"
+	POOL_ACTION_ADD    = "addLiquidity"
+	POOL_ACTION_REMOVE = "removeLiquidity"
+	POOL_ACTION_SWAP   = "swap"
+	POOL_ACTION_FEE    = "updateFee"
+)
+
+// LP fee of pools is in basis points of the swapped amount and stays in the pool reserves
+const (
+	DEFAULT_POOL_FEE_BPS = 30
+	MAX_POOL_FEE_BPS     = 1000
+	POOL_FEE_DENOMINATOR = 10000
+)
+
+// MINIMUM_LIQUIDITY shares locked in the pool address on first deposit so that the pool can never be drained to zero
+const MINIMUM_LIQUIDITY = 1000
+
+const LP_TOKEN_DECIMALS = 18
+
+// LP_TOKEN_PREFIX symbols of LP tokens are longer than issued symbols can be, so they never collide
+const LP_TOKEN_PREFIX = "LP-"
+
+// BusyAMM contract
+type BusyAMM struct {
+	contractapi.Contract
+}
+
+// CreateLiquidityPool create constant product pool of BUSY20 token and BUSY together with its LP token
+func (ba *BusyAMM) CreateLiquidityPool(ctx contractapi.TransactionContextInterface, tokenA string, tokenB string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	pairSymbol := tokenA
+	if strings.ToUpper(tokenA) == BUSY_COIN_SYMBOL {
+		pairSymbol = tokenB
+	}
+	if strings.ToUpper(tokenA) != BUSY_COIN_SYMBOL && strings.ToUpper(tokenB) != BUSY_COIN_SYMBOL {
+		response.Message = fmt.Sprintf("One of the pool tokens has to be %s", BUSY_COIN_SYMBOL)
+		logger.Error(response.Message)
+		return response, generateError(412, "CLPL001", response.Message)
+	}
+	if strings.HasPrefix(strings.ToUpper(pairSymbol), LP_TOKEN_PREFIX) {
+		response.Message = "LP tokens can not be pooled"
+		logger.Error(response.Message)
+		return response, generateError(412, "CLPL002", response.Message)
+	}
+	base, quote, err := parsePair(ctx, pairSymbol+"/"+BUSY_COIN_SYMBOL)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "CLPL003", response.Message)
+	}
+	pair := base + "/" + quote
+
+	poolAsBytes, err := ctx.GetStub().GetState(getLiquidityPoolKey(pair))
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while getting state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL004", response.Message)
+	}
+	lpSymbol := LP_TOKEN_PREFIX + strings.ToUpper(base)
+	exists, err := ifTokenExists(ctx, lpSymbol)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while getting state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL004", response.Message)
+	}
+	if poolAsBytes != nil || exists {
+		response.Message = fmt.Sprintf("Liquidity pool %s already exists", pair)
+		logger.Error(response.Message)
+		return response, generateError(409, "CLPL005", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	creator, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL006", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, creator)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "CLPL007", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "createPool")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, creator, bigTxFee, "createPool")
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "CLPL008", response.Message)
+	}
+
+	baseToken, _ := getBusy20Token(ctx, base)
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	pool := LiquidityPool{
+		DocType:      "liquidityPool",
+		ID:           pair,
+		Base:         base,
+		Quote:        quote,
+		LPToken:      lpSymbol,
+		Address:      getLiquidityPoolAddress(base),
+		ReserveBase:  bigZero.String(),
+		ReserveQuote: bigZero.String(),
+		TotalShares:  bigZero.String(),
+		FeeBps:       DEFAULT_POOL_FEE_BPS,
+		Creator:      creator,
+		CreatedAt:    uint64(now.Seconds),
+	}
+
+	// LP token is administered by the pool address, so nobody can mint or burn shares outside of the pool
+	tokenIdAsBytes, err := ctx.GetStub().GetState("latestTokenId")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching latest token id: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL009", response.Message)
+	}
+	latestTokenID, _ := strconv.Atoi(string(tokenIdAsBytes))
+	lpToken := Token{
+		DocType:     "token",
+		ID:          uint64(latestTokenID + 1),
+		TokenName:   fmt.Sprintf("%s %s LP", base, quote),
+		TokenSymbol: lpSymbol,
+		Admin:       pool.Address,
+		TotalSupply: bigZero.String(),
+		Decimals:    LP_TOKEN_DECIMALS,
+		MetaData: TokenMetaData{
+			Type:        TOKEN_TYPE_BUSY20,
+			Logo:        baseToken.MetaData.Logo,
+			Description: fmt.Sprintf("Liquidity shares of %s pool", pair),
+		},
+		TokenAddress: generateTokenStateAddress(lpSymbol),
+	}
+	_ = ctx.GetStub().PutState("latestTokenId", []byte(strconv.Itoa(latestTokenID+1)))
+	lpTokenAsBytes, _ := json.Marshal(lpToken)
+	err = ctx.GetStub().PutState(lpToken.TokenAddress, lpTokenAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while updating token on blockchain : %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL010", response.Message)
+	}
+	err = putLiquidityPool(ctx, &pool)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL010", response.Message)
+	}
+
+	err = chargeFee(ctx, creator, bigTxFee, "createPool")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL011", response.Message)
+	}
+
+	err = sendPoolEvent(ctx, &pool, POOL_ACTION_CREATE, creator, bigZero, bigZero, bigZero, []UserAddress{{Address: creator, Token: BUSY_COIN_SYMBOL}}, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pool event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CLPL012", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Liquidity pool %s has been successfully created", pair)
+	response.Success = true
+	response.Data = pool
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// AddLiquidity deposit base and BUSY into pool at its current ratio and mint LP shares to default wallet of invoker.
+// Amounts are maximums, only the part matching the pool ratio is taken
+func (ba *BusyAMM) AddLiquidity(ctx contractapi.TransactionContextInterface, pair string, baseAmount string, quoteAmount string, minShares string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ALIQ001", response.Message)
+	}
+	bigBaseAmount, ok := parseAmount(ctx, baseAmount, pool.Base)
+	if !ok || bigBaseAmount.Cmp(bigZero) != 1 {
+		response.Message = "Base amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "ALIQ002", response.Message)
+	}
+	bigQuoteAmount, ok := parseAmount(ctx, quoteAmount, pool.Quote)
+	if !ok || bigQuoteAmount.Cmp(bigZero) != 1 {
+		response.Message = "Quote amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "ALIQ003", response.Message)
+	}
+	bigMinShares, ok := parseAmount(ctx, minShares, pool.LPToken)
+	if !ok || bigMinShares.Sign() == -1 {
+		response.Message = "Minimum shares have to be a number not less than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "ALIQ004", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	provider, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALIQ005", response.Message)
+	}
+
+	reserveBase, _ := new(big.Int).SetString(pool.ReserveBase, 10)
+	reserveQuote, _ := new(big.Int).SetString(pool.ReserveQuote, 10)
+	totalShares, _ := new(big.Int).SetString(pool.TotalShares, 10)
+	lockedShares := new(big.Int).Set(bigZero)
+	var shares *big.Int
+	if totalShares.Cmp(bigZero) == 0 {
+		lockedShares = big.NewInt(MINIMUM_LIQUIDITY)
+		shares = new(big.Int).Mul(bigBaseAmount, bigQuoteAmount)
+		shares.Sqrt(shares).Sub(shares, lockedShares)
+	} else {
+		optimalQuote := new(big.Int).Mul(bigBaseAmount, reserveQuote)
+		optimalQuote.Quo(optimalQuote, reserveBase)
+		if optimalQuote.Cmp(bigQuoteAmount) != 1 {
+			bigQuoteAmount = optimalQuote
+		} else {
+			optimalBase := new(big.Int).Mul(bigQuoteAmount, reserveBase)
+			bigBaseAmount = optimalBase.Quo(optimalBase, reserveQuote)
+		}
+		shares = new(big.Int).Mul(bigBaseAmount, totalShares)
+		shares.Quo(shares, reserveBase)
+		quoteShares := new(big.Int).Mul(bigQuoteAmount, totalShares)
+		quoteShares.Quo(quoteShares, reserveQuote)
+		if quoteShares.Cmp(shares) == -1 {
+			shares = quoteShares
+		}
+	}
+	if shares.Cmp(bigZero) != 1 {
+		response.Message = "Deposit is too small to mint any shares"
+		logger.Error(response.Message)
+		return response, generateError(412, "ALIQ006", response.Message)
+	}
+	if shares.Cmp(bigMinShares) == -1 {
+		response.Message = fmt.Sprintf("Deposit would mint %s shares which is less than minimum %s", shares.String(), bigMinShares.String())
+		logger.Error(response.Message)
+		return response, generateError(412, "ALIQ007", response.Message)
+	}
+
+	// fee is charged with the BUSY leg as both legs debit provider and BUSY can be debited only once
+	txFee, _ := getTxFee(ctx, "addLiquidity")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	err = transferHelper(ctx, provider, pool.Address, bigBaseAmount, pool.Base, bigZero, "addLiquidity")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while depositing %s: %s", pool.Base, err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "ALIQ008", response.Message)
+	}
+	err = transferHelper(ctx, provider, pool.Address, bigQuoteAmount, pool.Quote, bigTxFee, "addLiquidity")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while depositing %s: %s", pool.Quote, err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "ALIQ009", response.Message)
+	}
+	err = routeFee(ctx, "addLiquidity", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALIQ010", response.Message)
+	}
+
+	err = addUTXO(ctx, provider, shares, pool.LPToken)
+	if err == nil && lockedShares.Cmp(bigZero) == 1 {
+		err = addUTXO(ctx, pool.Address, lockedShares, pool.LPToken)
+	}
+	if err == nil {
+		err = addTotalSupplyUTXO(ctx, pool.LPToken, new(big.Int).Add(shares, lockedShares))
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while minting shares: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALIQ011", response.Message)
+	}
+
+	pool.ReserveBase = reserveBase.Add(reserveBase, bigBaseAmount).String()
+	pool.ReserveQuote = reserveQuote.Add(reserveQuote, bigQuoteAmount).String()
+	pool.TotalShares = totalShares.Add(totalShares, shares).Add(totalShares, lockedShares).String()
+	err = putLiquidityPool(ctx, pool)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALIQ012", response.Message)
+	}
+
+	userAddresses := []UserAddress{
+		{Address: provider, Token: pool.Base},
+		{Address: provider, Token: pool.Quote},
+		{Address: provider, Token: pool.LPToken},
+	}
+	err = sendPoolEvent(ctx, pool, POOL_ACTION_ADD, provider, bigBaseAmount, bigQuoteAmount, shares, userAddresses, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pool event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ALIQ013", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s shares of %s have been successfully minted", shares.String(), pool.ID)
+	response.Success = true
+	response.Data = pool
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RemoveLiquidity burn LP shares of default wallet of invoker and withdraw their part of pool reserves
+func (ba *BusyAMM) RemoveLiquidity(ctx contractapi.TransactionContextInterface, pair string, shares string, minBaseAmount string, minQuoteAmount string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "RLIQ001", response.Message)
+	}
+	bigShares, ok := parseAmount(ctx, shares, pool.LPToken)
+	if !ok || bigShares.Cmp(bigZero) != 1 {
+		response.Message = "Shares have to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "RLIQ002", response.Message)
+	}
+	bigMinBase, ok := parseAmount(ctx, minBaseAmount, pool.Base)
+	if !ok || bigMinBase.Sign() == -1 {
+		response.Message = "Minimum base amount has to be a number not less than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "RLIQ003", response.Message)
+	}
+	bigMinQuote, ok := parseAmount(ctx, minQuoteAmount, pool.Quote)
+	if !ok || bigMinQuote.Sign() == -1 {
+		response.Message = "Minimum quote amount has to be a number not less than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "RLIQ004", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	provider, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ005", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, provider)
+	if err == nil {
+		err = checkTokenNotPaused(ctx, pool.LPToken)
+	}
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "RLIQ006", response.Message)
+	}
+	balance, err := getBalanceHelper(ctx, provider, pool.LPToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching balance: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ007", response.Message)
+	}
+	if balance.Cmp(bigShares) == -1 {
+		response.Message = fmt.Sprintf("Shares %s higher then your balance %s", bigShares.String(), balance.String())
+		logger.Error(response.Message)
+		return response, generateError(402, "RLIQ008", response.Message)
+	}
+
+	reserveBase, _ := new(big.Int).SetString(pool.ReserveBase, 10)
+	reserveQuote, _ := new(big.Int).SetString(pool.ReserveQuote, 10)
+	totalShares, _ := new(big.Int).SetString(pool.TotalShares, 10)
+	baseOut := new(big.Int).Mul(bigShares, reserveBase)
+	baseOut.Quo(baseOut, totalShares)
+	quoteOut := new(big.Int).Mul(bigShares, reserveQuote)
+	quoteOut.Quo(quoteOut, totalShares)
+	if baseOut.Cmp(bigMinBase) == -1 || quoteOut.Cmp(bigMinQuote) == -1 {
+		response.Message = fmt.Sprintf("Withdrawal of %s %s and %s %s is less than requested minimum", baseOut.String(), pool.Base, quoteOut.String(), pool.Quote)
+		logger.Error(response.Message)
+		return response, generateError(412, "RLIQ009", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "removeLiquidity")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	feeBalance := getFeePayerBalance(ctx, provider, bigTxFee, "removeLiquidity", pool.LPToken)
+	if feeBalance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "RLIQ010", response.Message)
+	}
+
+	negativeShares := new(big.Int).Neg(bigShares)
+	err = addUTXO(ctx, provider, negativeShares, pool.LPToken)
+	if err == nil {
+		err = addTotalSupplyUTXO(ctx, pool.LPToken, negativeShares)
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while burning shares: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ011", response.Message)
+	}
+	err = transferHelper(ctx, pool.Address, provider, baseOut, pool.Base, bigZero, "removeLiquidity")
+	if err == nil {
+		err = transferHelper(ctx, pool.Address, provider, quoteOut, pool.Quote, bigZero, "removeLiquidity")
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while withdrawing liquidity: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ012", response.Message)
+	}
+	err = chargeFee(ctx, provider, bigTxFee, "removeLiquidity", pool.LPToken)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ013", response.Message)
+	}
+
+	pool.ReserveBase = reserveBase.Sub(reserveBase, baseOut).String()
+	pool.ReserveQuote = reserveQuote.Sub(reserveQuote, quoteOut).String()
+	pool.TotalShares = totalShares.Sub(totalShares, bigShares).String()
+	err = putLiquidityPool(ctx, pool)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ014", response.Message)
+	}
+
+	userAddresses := []UserAddress{
+		{Address: provider, Token: pool.Base},
+		{Address: provider, Token: pool.Quote},
+		{Address: provider, Token: pool.LPToken},
+	}
+	err = sendPoolEvent(ctx, pool, POOL_ACTION_REMOVE, provider, baseOut, quoteOut, bigShares, userAddresses, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pool event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RLIQ015", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s shares of %s have been successfully burned", bigShares.String(), pool.ID)
+	response.Success = true
+	response.Data = pool
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// Swap sell amountIn of tokenIn to pool for the other token of the pair, minAmountOut limits the slippage
+func (ba *BusyAMM) Swap(ctx contractapi.TransactionContextInterface, pair string, tokenIn string, amountIn string, minAmountOut string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "PSWP001", response.Message)
+	}
+	tokenIn, tokenOut, err := getPoolSwapTokens(pool, tokenIn)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "PSWP002", response.Message)
+	}
+	bigAmountIn, ok := parseAmount(ctx, amountIn, tokenIn)
+	if !ok || bigAmountIn.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PSWP003", response.Message)
+	}
+	bigMinAmountOut, ok := parseAmount(ctx, minAmountOut, tokenOut)
+	if !ok || bigMinAmountOut.Sign() == -1 {
+		response.Message = "Minimum amount out has to be a number not less than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PSWP004", response.Message)
+	}
+
+	reserveIn, reserveOut := getPoolReserves(pool, tokenIn)
+	if reserveIn.Cmp(bigZero) == 0 || reserveOut.Cmp(bigZero) == 0 {
+		response.Message = fmt.Sprintf("Liquidity pool %s has no liquidity", pool.ID)
+		logger.Error(response.Message)
+		return response, generateError(409, "PSWP005", response.Message)
+	}
+	amountOut := getPoolAmountOut(bigAmountIn, reserveIn, reserveOut, pool.FeeBps)
+	if amountOut.Cmp(bigZero) != 1 {
+		response.Message = "Amount is too small to get anything out of the pool"
+		logger.Error(response.Message)
+		return response, generateError(412, "PSWP006", response.Message)
+	}
+	if amountOut.Cmp(bigMinAmountOut) == -1 {
+		response.Message = fmt.Sprintf("Swap would return %s %s which is less than minimum %s", amountOut.String(), tokenOut, bigMinAmountOut.String())
+		logger.Error(response.Message)
+		return response, generateError(412, "PSWP007", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	trader, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PSWP008", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "poolSwap")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	err = transferHelper(ctx, trader, pool.Address, bigAmountIn, tokenIn, bigTxFee, "poolSwap")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while paying %s: %s", tokenIn, err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "PSWP009", response.Message)
+	}
+	err = transferHelper(ctx, pool.Address, trader, amountOut, tokenOut, bigZero, "poolSwap")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while paying out %s: %s", tokenOut, err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PSWP010", response.Message)
+	}
+	err = routeFee(ctx, "poolSwap", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PSWP011", response.Message)
+	}
+
+	reserveIn.Add(reserveIn, bigAmountIn)
+	reserveOut.Sub(reserveOut, amountOut)
+	if tokenIn == pool.Base {
+		pool.ReserveBase, pool.ReserveQuote = reserveIn.String(), reserveOut.String()
+	} else {
+		pool.ReserveBase, pool.ReserveQuote = reserveOut.String(), reserveIn.String()
+	}
+	err = putLiquidityPool(ctx, pool)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PSWP012", response.Message)
+	}
+
+	baseAmount, quoteAmount := bigAmountIn, amountOut
+	if tokenIn != pool.Base {
+		baseAmount, quoteAmount = amountOut, bigAmountIn
+	}
+	userAddresses := []UserAddress{
+		{Address: trader, Token: pool.Base},
+		{Address: trader, Token: pool.Quote},
+	}
+	err = sendPoolEvent(ctx, pool, POOL_ACTION_SWAP, trader, baseAmount, quoteAmount, bigZero, userAddresses, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pool event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PSWP013", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s %s has been successfully swapped for %s %s", bigAmountIn.String(), tokenIn, amountOut.String(), tokenOut)
+	response.Success = true
+	response.Data = SwapQuote{
+		Pair:      pool.ID,
+		TokenIn:   tokenIn,
+		AmountIn:  bigAmountIn.String(),
+		TokenOut:  tokenOut,
+		AmountOut: amountOut.String(),
+	}
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// UpdatePoolFee update LP fee of pool in basis points, only network admin can do it
+func (ba *BusyAMM) UpdatePoolFee(ctx contractapi.TransactionContextInterface, pair string, feeBps uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to update pool fee"
+		logger.Error(response.Message)
+		return response, generateError(403, "UPFE001", response.Message)
+	}
+	if feeBps > MAX_POOL_FEE_BPS {
+		response.Message = fmt.Sprintf("Pool fee can not be higher than %d basis points", MAX_POOL_FEE_BPS)
+		logger.Error(response.Message)
+		return response, generateError(412, "UPFE002", response.Message)
+	}
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "UPFE003", response.Message)
+	}
+
+	pool.FeeBps = feeBps
+	err = putLiquidityPool(ctx, pool)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UPFE004", response.Message)
+	}
+
+	err = sendPoolEvent(ctx, pool, POOL_ACTION_FEE, "", bigZero, bigZero, bigZero, []UserAddress{}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pool event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "UPFE005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Fee of liquidity pool %s has been successfully updated to %d basis points", pool.ID, feeBps)
+	response.Success = true
+	response.Data = pool
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetLiquidityPool get pool details with its reserves and total shares
+func (ba *BusyAMM) GetLiquidityPool(ctx contractapi.TransactionContextInterface, pair string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GLPL001", response.Message)
+	}
+
+	response.Message = "Liquidity pool has been successfully fetched"
+	response.Success = true
+	response.Data = pool
+	return response, nil
+}
+
+// GetPoolPrice get spot price of pool, price is in base units of the other token per one whole token
+func (ba *BusyAMM) GetPoolPrice(ctx contractapi.TransactionContextInterface, pair string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GPPR001", response.Message)
+	}
+	reserveBase, _ := new(big.Int).SetString(pool.ReserveBase, 10)
+	reserveQuote, _ := new(big.Int).SetString(pool.ReserveQuote, 10)
+	if reserveBase.Cmp(bigZero) == 0 || reserveQuote.Cmp(bigZero) == 0 {
+		response.Message = fmt.Sprintf("Liquidity pool %s has no liquidity", pool.ID)
+		logger.Error(response.Message)
+		return response, generateError(409, "GPPR002", response.Message)
+	}
+	baseUnit, err := getBaseUnit(ctx, pool.Base)
+	if err == nil {
+		var quoteUnit *big.Int
+		quoteUnit, err = getBaseUnit(ctx, pool.Quote)
+		if err == nil {
+			price := new(big.Int).Mul(reserveQuote, baseUnit)
+			inversePrice := new(big.Int).Mul(reserveBase, quoteUnit)
+			response.Data = PoolPrice{
+				Pair:         pool.ID,
+				Price:        price.Quo(price, reserveBase).String(),
+				InversePrice: inversePrice.Quo(inversePrice, reserveQuote).String(),
+				ReserveBase:  pool.ReserveBase,
+				ReserveQuote: pool.ReserveQuote,
+			}
+		}
+	}
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token decimals: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GPPR003", response.Message)
+	}
+
+	response.Message = "Pool price has been successfully fetched"
+	response.Success = true
+	return response, nil
+}
+
+// GetSwapQuote get amount Swap would currently return for amountIn of tokenIn
+func (ba *BusyAMM) GetSwapQuote(ctx contractapi.TransactionContextInterface, pair string, tokenIn string, amountIn string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	pool, err := getLiquidityPool(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GSWQ001", response.Message)
+	}
+	tokenIn, tokenOut, err := getPoolSwapTokens(pool, tokenIn)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "GSWQ002", response.Message)
+	}
+	bigAmountIn, ok := parseAmount(ctx, amountIn, tokenIn)
+	if !ok || bigAmountIn.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "GSWQ003", response.Message)
+	}
+	reserveIn, reserveOut := getPoolReserves(pool, tokenIn)
+	if reserveIn.Cmp(bigZero) == 0 || reserveOut.Cmp(bigZero) == 0 {
+		response.Message = fmt.Sprintf("Liquidity pool %s has no liquidity", pool.ID)
+		logger.Error(response.Message)
+		return response, generateError(409, "GSWQ004", response.Message)
+	}
+
+	response.Message = "Swap quote has been successfully fetched"
+	response.Success = true
+	response.Data = SwapQuote{
+		Pair:      pool.ID,
+		TokenIn:   tokenIn,
+		AmountIn:  bigAmountIn.String(),
+		TokenOut:  tokenOut,
+		AmountOut: getPoolAmountOut(bigAmountIn, reserveIn, reserveOut, pool.FeeBps).String(),
+	}
+	return response, nil
+}
+
+// getPoolAmountOut constant product output for amountIn after LP fee, rounded down in favour of the pool
+func getPoolAmountOut(amountIn *big.Int, reserveIn *big.Int, reserveOut *big.Int, feeBps uint64) *big.Int {
+	amountInWithFee := new(big.Int).Mul(amountIn, new(big.Int).SetUint64(POOL_FEE_DENOMINATOR-feeBps))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(POOL_FEE_DENOMINATOR))
+	denominator.Add(denominator, amountInWithFee)
+	return numerator.Quo(numerator, denominator)
+}
+
+// getPoolSwapTokens symbols of token going into and out of pool
+func getPoolSwapTokens(pool *LiquidityPool, tokenIn string) (string, string, error) {
+	switch {
+	case strings.EqualFold(tokenIn, pool.Base):
+		return pool.Base, pool.Quote, nil
+	case strings.EqualFold(tokenIn, pool.Quote):
+		return pool.Quote, pool.Base, nil
+	default:
+		return "", "", fmt.Errorf("token %s is not part of liquidity pool %s", tokenIn, pool.ID)
+	}
+}
+
+// getPoolReserves reserves of pool ordered by swap direction
+func getPoolReserves(pool *LiquidityPool, tokenIn string) (*big.Int, *big.Int) {
+	reserveBase, _ := new(big.Int).SetString(pool.ReserveBase, 10)
+	reserveQuote, _ := new(big.Int).SetString(pool.ReserveQuote, 10)
+	if tokenIn == pool.Base {
+		return reserveBase, reserveQuote
+	}
+	return reserveQuote, reserveBase
+}
+
+// getLiquidityPoolAddress address holding reserves of pool
+func getLiquidityPoolAddress(base string) string {
+	return "pool-" + base
+}
+
+func getLiquidityPoolKey(pair string) string {
+	return fmt.Sprintf("liquidityPool~%s", pair)
+}
+
+func getLiquidityPool(ctx contractapi.TransactionContextInterface, pair string) (*LiquidityPool, error) {
+	base, quote, err := parsePair(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+	poolAsBytes, err := ctx.GetStub().GetState(getLiquidityPoolKey(base + "/" + quote))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching liquidity pool: %s", err.Error())
+	}
+	if poolAsBytes == nil {
+		return nil, fmt.Errorf("liquidity pool %s does not exist", pair)
+	}
+	pool := &LiquidityPool{}
+	if err := json.Unmarshal(poolAsBytes, pool); err != nil {
+		return nil, fmt.Errorf("error while retrieving liquidity pool: %s", err.Error())
+	}
+	return pool, nil
+}
+
+func putLiquidityPool(ctx contractapi.TransactionContextInterface, pool *LiquidityPool) error {
+	poolAsBytes, _ := json.Marshal(pool)
+	return ctx.GetStub().PutState(getLiquidityPoolKey(pool.ID), poolAsBytes)
+}
+
+func sendPoolEvent(ctx contractapi.TransactionContextInterface, pool *LiquidityPool, action string, account string, baseAmount *big.Int, quoteAmount *big.Int, shares *big.Int, userAddresses []UserAddress, fee *big.Int) error {
+	poolData := PoolEvent{
+		LiquidityPool:  *pool,
+		Action:         action,
+		Account:        account,
+		BaseAmount:     baseAmount.String(),
+		QuoteAmount:    quoteAmount.String(),
+		Shares:         shares.String(),
+		UserAddresses:  userAddresses,
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	poolAsBytes, _ := json.Marshal(poolData)
+	return ctx.GetStub().SetEvent(POOL_EVENT, poolAsBytes)
+}
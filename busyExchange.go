@@ -0,0 +1,676 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const EXCHANGE_EVENT = "EXCHANGE"
+
+// exchangeBookPrefix composite key prefix of resting orders, keys of a side sort by best price first and then by time
+const exchangeBookPrefix = "pair~side~price~timestamp~orderId"
+
+// exchangeTradePrefix composite key prefix of executed trades ordered by time
+const exchangeTradePrefix = "pair~timestamp~tradeId"
+
+const (
+	ORDER_SIDE_BUY  = "buy"
+	ORDER_SIDE_SELL = "sell"
+)
+
+const (
+	ORDER_STATUS_OPEN      = "open"
+	ORDER_STATUS_PARTIAL   = "partial"
+	ORDER_STATUS_FILLED    = "filled"
+	ORDER_STATUS_CANCELLED = "cancelled"
+)
+
+// MAX_ORDER_MATCHES resting orders an incoming order can fill in one transaction, rest of it stays in the book
+const MAX_ORDER_MATCHES = 50
+
+// priceKeyDigits width of zero padded price in book keys, prices must be lower than 10^priceKeyDigits
+const priceKeyDigits = 40
+
+// BusyExchange contract
+type BusyExchange struct {
+	contractapi.Contract
+}
+
+// orderMatch fill of a resting order by an incoming order
+type orderMatch struct {
+	order   *Order
+	bookKey string
+	fill    *big.Int
+	quote   *big.Int
+}
+
+// PlaceOrder place limit order from default wallet of invoker on BASE/BUSY pair.
+// amount is in base token and price is BUSY per one whole base token, order is matched against the book and the rest is escrowed
+func (be *BusyExchange) PlaceOrder(ctx contractapi.TransactionContextInterface, pair string, side string, price string, amount string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	base, quote, err := parsePair(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "PORD001", response.Message)
+	}
+	pair = base + "/" + quote
+	if side != ORDER_SIDE_BUY && side != ORDER_SIDE_SELL {
+		response.Message = fmt.Sprintf("Side has to be %s or %s", ORDER_SIDE_BUY, ORDER_SIDE_SELL)
+		logger.Error(response.Message)
+		return response, generateError(412, "PORD002", response.Message)
+	}
+	bigPrice, ok := parseAmount(ctx, price, quote)
+	if !ok || bigPrice.Cmp(bigZero) != 1 || len(bigPrice.String()) > priceKeyDigits {
+		response.Message = "Price has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PORD003", response.Message)
+	}
+	bigAmount, ok := parseAmount(ctx, amount, base)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PORD004", response.Message)
+	}
+	baseUnit, err := getBaseUnit(ctx, base)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD005", response.Message)
+	}
+	if getQuoteAmount(bigAmount, bigPrice, baseUnit).Cmp(bigZero) != 1 {
+		response.Message = "Order value is too small"
+		logger.Error(response.Message)
+		return response, generateError(412, "PORD006", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	owner, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD007", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, owner)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "PORD008", response.Message)
+	}
+	err = checkTokenNotPaused(ctx, base)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "PORD009", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "placeOrder")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	order := Order{
+		DocType:     "order",
+		ID:          response.TxID,
+		Pair:        pair,
+		Base:        base,
+		Quote:       quote,
+		Side:        side,
+		Price:       bigPrice.String(),
+		Amount:      bigAmount.String(),
+		Remaining:   bigAmount.String(),
+		LockedQuote: bigZero.String(),
+		Owner:       owner,
+		Escrow:      getOrderEscrowAddress(response.TxID),
+		Status:      ORDER_STATUS_OPEN,
+		CreatedAt:   uint64(now.Seconds),
+		Timestamp:   fmt.Sprintf("%020d", now.Seconds*1e9+int64(now.Nanos)),
+	}
+
+	matches, err := matchOrder(ctx, &order, baseUnit)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while matching order: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD010", response.Message)
+	}
+
+	remaining := new(big.Int).Set(bigAmount)
+	for _, match := range matches {
+		remaining.Sub(remaining, match.fill)
+	}
+	restingLock := bigZero
+	if remaining.Cmp(bigZero) == 1 {
+		if side == ORDER_SIDE_BUY {
+			restingLock = getQuoteAmount(remaining, bigPrice, baseUnit)
+		} else {
+			restingLock = remaining
+		}
+	}
+
+	// invoker pays every maker directly and escrows the unfilled rest in a single transfer
+	payToken := base
+	if side == ORDER_SIDE_BUY {
+		payToken = quote
+	}
+	recipients := []string{}
+	amounts := []*big.Int{}
+	for _, match := range matches {
+		recipients = append(recipients, match.order.Owner)
+		if side == ORDER_SIDE_BUY {
+			amounts = append(amounts, match.quote)
+		} else {
+			amounts = append(amounts, match.fill)
+		}
+	}
+	recipients = append(recipients, order.Escrow)
+	amounts = append(amounts, restingLock)
+	err = multiTransferHelper(ctx, owner, recipients, amounts, payToken, bigTxFee, "placeOrder")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while paying for the order: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "PORD011", response.Message)
+	}
+	err = routeFee(ctx, "placeOrder", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD012", response.Message)
+	}
+
+	trades := []Trade{}
+	userAddresses := []UserAddress{{Address: owner, Token: BUSY_COIN_SYMBOL}, {Address: owner, Token: base}}
+	for i, match := range matches {
+		err = settleMakerOrder(ctx, match, owner)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while settling order %s: %s", match.order.ID, err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "PORD013", response.Message)
+		}
+		trade := Trade{
+			DocType:      "trade",
+			ID:           fmt.Sprintf("%s-%d", response.TxID, i),
+			Pair:         pair,
+			Price:        match.order.Price,
+			Amount:       match.fill.String(),
+			QuoteAmount:  match.quote.String(),
+			MakerOrderID: match.order.ID,
+			TakerOrderID: order.ID,
+			Maker:        match.order.Owner,
+			Taker:        owner,
+			TakerSide:    side,
+			CreatedAt:    uint64(now.Seconds),
+		}
+		err = putTrade(ctx, &trade, order.Timestamp)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "PORD014", response.Message)
+		}
+		trades = append(trades, trade)
+		userAddresses = append(userAddresses, UserAddress{Address: match.order.Owner, Token: BUSY_COIN_SYMBOL}, UserAddress{Address: match.order.Owner, Token: base})
+	}
+
+	order.Remaining = remaining.String()
+	if side == ORDER_SIDE_BUY {
+		order.LockedQuote = restingLock.String()
+	}
+	switch {
+	case remaining.Cmp(bigZero) == 0:
+		order.Status = ORDER_STATUS_FILLED
+	case restingLock.Cmp(bigZero) == 0:
+		// rest of the order is worth less than the smallest unit of quote token
+		order.Status = ORDER_STATUS_CANCELLED
+	case len(matches) > 0:
+		order.Status = ORDER_STATUS_PARTIAL
+	}
+	if order.Status == ORDER_STATUS_OPEN || order.Status == ORDER_STATUS_PARTIAL {
+		bookKey, _ := getOrderBookKey(ctx, &order)
+		err = ctx.GetStub().PutState(bookKey, []byte(order.ID))
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "PORD014", response.Message)
+		}
+	}
+	err = putOrder(ctx, &order)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD014", response.Message)
+	}
+
+	err = sendExchangeEvent(ctx, &order, trades, userAddresses, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the exchange event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PORD015", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Order %s has been successfully placed with %d trades", order.ID, len(trades))
+	response.Success = true
+	response.Data = map[string]interface{}{
+		"order":  order,
+		"trades": trades,
+	}
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CancelOrder remove open order of invoker from the book and refund its escrow
+func (be *BusyExchange) CancelOrder(ctx contractapi.TransactionContextInterface, orderId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	order, err := getOrder(ctx, orderId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "CORD001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	_, err = resolveSenderWallet(ctx, commonName, order.Owner)
+	if err != nil {
+		response.Message = "Only owner can cancel the order"
+		logger.Error(response.Message)
+		return response, generateError(403, "CORD002", response.Message)
+	}
+	if order.Status != ORDER_STATUS_OPEN && order.Status != ORDER_STATUS_PARTIAL {
+		response.Message = fmt.Sprintf("Order %s has already been %s", orderId, order.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "CORD003", response.Message)
+	}
+
+	refundToken := order.Base
+	refund, _ := new(big.Int).SetString(order.Remaining, 10)
+	if order.Side == ORDER_SIDE_BUY {
+		refundToken = order.Quote
+		refund, _ = new(big.Int).SetString(order.LockedQuote, 10)
+	}
+	err = transferHelper(ctx, order.Escrow, order.Owner, refund, refundToken, bigZero, "cancelOrder")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while refunding order: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CORD004", response.Message)
+	}
+	bookKey, _ := getOrderBookKey(ctx, order)
+	err = ctx.GetStub().DelState(bookKey)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CORD005", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	order.Status = ORDER_STATUS_CANCELLED
+	order.LockedQuote = bigZero.String()
+	order.UpdatedAt = uint64(now.Seconds)
+	err = putOrder(ctx, order)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CORD005", response.Message)
+	}
+
+	err = sendExchangeEvent(ctx, order, []Trade{}, []UserAddress{{Address: order.Owner, Token: refundToken}}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the exchange event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CORD006", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Order %s has been successfully cancelled", orderId)
+	response.Success = true
+	response.Data = order
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetOrder get order details
+func (be *BusyExchange) GetOrder(ctx contractapi.TransactionContextInterface, orderId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	order, err := getOrder(ctx, orderId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GORD001", response.Message)
+	}
+
+	response.Message = "Order has been successfully fetched"
+	response.Success = true
+	response.Data = order
+	return response, nil
+}
+
+// GetOrderBook get up to depth best price levels of both sides of pair
+func (be *BusyExchange) GetOrderBook(ctx contractapi.TransactionContextInterface, pair string, depth int) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if depth <= 0 {
+		response.Message = "Depth has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "GORB001", response.Message)
+	}
+	base, quote, err := parsePair(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "GORB002", response.Message)
+	}
+	pair = base + "/" + quote
+
+	orderBook := OrderBook{Pair: pair}
+	for _, side := range []string{ORDER_SIDE_BUY, ORDER_SIDE_SELL} {
+		levels, err := getOrderBookLevels(ctx, pair, side, depth)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching order book: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "GORB003", response.Message)
+		}
+		if side == ORDER_SIDE_BUY {
+			orderBook.Bids = levels
+		} else {
+			orderBook.Asks = levels
+		}
+	}
+
+	response.Message = "Order book has been successfully fetched"
+	response.Success = true
+	response.Data = orderBook
+	return response, nil
+}
+
+// GetTradeHistory get trades of pair, oldest first
+func (be *BusyExchange) GetTradeHistory(ctx contractapi.TransactionContextInterface, pair string, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "GTRH001", response.Message)
+	}
+	base, quote, err := parsePair(ctx, pair)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "GTRH002", response.Message)
+	}
+	resultIterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(exchangeTradePrefix, []string{base + "/" + quote}, pageSize, bookmark)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching trades: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "GTRH003", response.Message)
+	}
+	defer resultIterator.Close()
+
+	trades := []Trade{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error occurred while fetching trades: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "GTRH003", response.Message)
+		}
+		var trade Trade
+		_ = json.Unmarshal(data.Value, &trade)
+		trades = append(trades, trade)
+	}
+
+	response.Message = "Trades have been successfully fetched"
+	response.Success = true
+	response.Data = PagedResult{
+		Records:             trades,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}
+	return response, nil
+}
+
+// parsePair split BASE/BUSY pair, base has to be an issued BUSY20 token and is returned in the case it was issued with
+func parsePair(ctx contractapi.TransactionContextInterface, pair string) (string, string, error) {
+	symbols := strings.Split(strings.ToUpper(pair), "/")
+	if len(symbols) != 2 || symbols[1] != BUSY_COIN_SYMBOL || symbols[0] == BUSY_COIN_SYMBOL {
+		return "", "", fmt.Errorf("pair %s is invalid, it has to be in BASE/%s format", pair, BUSY_COIN_SYMBOL)
+	}
+	token, err := getBusy20Token(ctx, symbols[0])
+	if err != nil {
+		return "", "", err
+	}
+	return token.TokenSymbol, symbols[1], nil
+}
+
+// getBaseUnit base units in one whole base token
+func getBaseUnit(ctx contractapi.TransactionContextInterface, base string) (*big.Int, error) {
+	decimals, err := getTokenDecimals(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(decimals), nil), nil
+}
+
+// getQuoteAmount BUSY paid for amount of base at price, rounded down
+func getQuoteAmount(amount *big.Int, price *big.Int, baseUnit *big.Int) *big.Int {
+	quote := new(big.Int).Mul(amount, price)
+	return quote.Quo(quote, baseUnit)
+}
+
+// matchOrder fill incoming order against best resting orders of the other side, price of resting order is the trade price.
+// Orders of the same owner and of frozen wallets are skipped.
+func matchOrder(ctx contractapi.TransactionContextInterface, order *Order, baseUnit *big.Int) ([]orderMatch, error) {
+	matches := []orderMatch{}
+	oppositeSide := ORDER_SIDE_SELL
+	if order.Side == ORDER_SIDE_SELL {
+		oppositeSide = ORDER_SIDE_BUY
+	}
+	limitPrice, _ := new(big.Int).SetString(order.Price, 10)
+	remaining, _ := new(big.Int).SetString(order.Amount, 10)
+
+	bookIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(exchangeBookPrefix, []string{order.Pair, oppositeSide})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state for prefix %v: %v", exchangeBookPrefix, err)
+	}
+	defer bookIterator.Close()
+
+	for bookIterator.HasNext() && remaining.Cmp(bigZero) == 1 && len(matches) < MAX_ORDER_MATCHES {
+		data, err := bookIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the next state for prefix %v: %v", exchangeBookPrefix, err)
+		}
+		restingOrder, err := getOrder(ctx, string(data.Value))
+		if err != nil {
+			return nil, err
+		}
+		restingPrice, _ := new(big.Int).SetString(restingOrder.Price, 10)
+		if (order.Side == ORDER_SIDE_BUY && restingPrice.Cmp(limitPrice) == 1) || (order.Side == ORDER_SIDE_SELL && restingPrice.Cmp(limitPrice) == -1) {
+			break
+		}
+		if restingOrder.Owner == order.Owner || checkWalletNotFrozen(ctx, restingOrder.Owner) != nil {
+			continue
+		}
+
+		fill, _ := new(big.Int).SetString(restingOrder.Remaining, 10)
+		if fill.Cmp(remaining) == 1 {
+			fill = new(big.Int).Set(remaining)
+		}
+		quote := getQuoteAmount(fill, restingPrice, baseUnit)
+		if quote.Cmp(bigZero) != 1 {
+			break
+		}
+		matches = append(matches, orderMatch{order: restingOrder, bookKey: data.Key, fill: fill, quote: quote})
+		remaining.Sub(remaining, fill)
+	}
+	return matches, nil
+}
+
+// settleMakerOrder pay filled part of resting order from its escrow to taker and update or close it.
+// Fully filled buy order gets its leftover rounding dust back in the same transfer as escrow can be debited only once.
+func settleMakerOrder(ctx contractapi.TransactionContextInterface, match orderMatch, taker string) error {
+	makerOrder := match.order
+	remaining, _ := new(big.Int).SetString(makerOrder.Remaining, 10)
+	remaining.Sub(remaining, match.fill)
+	makerOrder.Remaining = remaining.String()
+
+	if makerOrder.Side == ORDER_SIDE_SELL {
+		err := transferHelper(ctx, makerOrder.Escrow, taker, match.fill, makerOrder.Base, bigZero, "trade")
+		if err != nil {
+			return err
+		}
+	} else {
+		lockedQuote, _ := new(big.Int).SetString(makerOrder.LockedQuote, 10)
+		lockedQuote.Sub(lockedQuote, match.quote)
+		recipients := []string{taker}
+		amounts := []*big.Int{match.quote}
+		if remaining.Cmp(bigZero) == 0 && lockedQuote.Cmp(bigZero) == 1 {
+			recipients = append(recipients, makerOrder.Owner)
+			amounts = append(amounts, new(big.Int).Set(lockedQuote))
+			lockedQuote = new(big.Int).Set(bigZero)
+		}
+		err := multiTransferHelper(ctx, makerOrder.Escrow, recipients, amounts, makerOrder.Quote, bigZero, "trade")
+		if err != nil {
+			return err
+		}
+		makerOrder.LockedQuote = lockedQuote.String()
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	makerOrder.UpdatedAt = uint64(now.Seconds)
+	if remaining.Cmp(bigZero) == 0 {
+		makerOrder.Status = ORDER_STATUS_FILLED
+		err := ctx.GetStub().DelState(match.bookKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		makerOrder.Status = ORDER_STATUS_PARTIAL
+	}
+	return putOrder(ctx, makerOrder)
+}
+
+// getOrderBookLevels aggregate resting orders of side into price levels, best price first
+func getOrderBookLevels(ctx contractapi.TransactionContextInterface, pair string, side string, depth int) ([]OrderBookLevel, error) {
+	levels := []OrderBookLevel{}
+	bookIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(exchangeBookPrefix, []string{pair, side})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state for prefix %v: %v", exchangeBookPrefix, err)
+	}
+	defer bookIterator.Close()
+
+	for bookIterator.HasNext() {
+		data, err := bookIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the next state for prefix %v: %v", exchangeBookPrefix, err)
+		}
+		order, err := getOrder(ctx, string(data.Value))
+		if err != nil {
+			return nil, err
+		}
+		remaining, _ := new(big.Int).SetString(order.Remaining, 10)
+		if len(levels) > 0 && levels[len(levels)-1].Price == order.Price {
+			level := &levels[len(levels)-1]
+			amount, _ := new(big.Int).SetString(level.Amount, 10)
+			level.Amount = amount.Add(amount, remaining).String()
+			level.Orders++
+			continue
+		}
+		if len(levels) == depth {
+			break
+		}
+		levels = append(levels, OrderBookLevel{Price: order.Price, Amount: remaining.String(), Orders: 1})
+	}
+	return levels, nil
+}
+
+// getOrderBookKey book key of order, bid prices are inverted so that the highest bid sorts first
+func getOrderBookKey(ctx contractapi.TransactionContextInterface, order *Order) (string, error) {
+	price, _ := new(big.Int).SetString(order.Price, 10)
+	if order.Side == ORDER_SIDE_BUY {
+		maxPrice := new(big.Int).Exp(big.NewInt(10), big.NewInt(priceKeyDigits), nil)
+		price = maxPrice.Sub(maxPrice, big.NewInt(1)).Sub(maxPrice, price)
+	}
+	priceKey := fmt.Sprintf("%0*s", priceKeyDigits, price.String())
+	return ctx.GetStub().CreateCompositeKey(exchangeBookPrefix, []string{order.Pair, order.Side, priceKey, order.Timestamp, order.ID})
+}
+
+// getOrderEscrowAddress every order has its own escrow address so that filling one never touches funds of another
+func getOrderEscrowAddress(id string) string {
+	return "order-" + id
+}
+
+func getOrder(ctx contractapi.TransactionContextInterface, orderId string) (*Order, error) {
+	orderAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("order~%s", orderId))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching order: %s", err.Error())
+	}
+	if orderAsBytes == nil {
+		return nil, fmt.Errorf("order %s does not exist", orderId)
+	}
+	order := &Order{}
+	if err := json.Unmarshal(orderAsBytes, order); err != nil {
+		return nil, fmt.Errorf("error while retrieving order: %s", err.Error())
+	}
+	return order, nil
+}
+
+func putOrder(ctx contractapi.TransactionContextInterface, order *Order) error {
+	orderAsBytes, _ := json.Marshal(order)
+	return ctx.GetStub().PutState(fmt.Sprintf("order~%s", order.ID), orderAsBytes)
+}
+
+func putTrade(ctx contractapi.TransactionContextInterface, trade *Trade, timestamp string) error {
+	tradeKey, err := ctx.GetStub().CreateCompositeKey(exchangeTradePrefix, []string{trade.Pair, timestamp, trade.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", exchangeTradePrefix, err)
+	}
+	tradeAsBytes, _ := json.Marshal(trade)
+	return ctx.GetStub().PutState(tradeKey, tradeAsBytes)
+}
+
+func sendExchangeEvent(ctx contractapi.TransactionContextInterface, order *Order, trades []Trade, userAddresses []UserAddress, fee *big.Int) error {
+	exchangeData := ExchangeEvent{
+		Order:          *order,
+		Trades:         trades,
+		UserAddresses:  userAddresses,
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	exchangeAsBytes, _ := json.Marshal(exchangeData)
+	return ctx.GetStub().SetEvent(EXCHANGE_EVENT, exchangeAsBytes)
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const HTLC_EVENT = "HTLC"
+
+const (
+	HTLC_STATUS_LOCKED   = "locked"
+	HTLC_STATUS_CLAIMED  = "claimed"
+	HTLC_STATUS_REFUNDED = "refunded"
+)
+
+// LockHTLC lock amount of token from default wallet of invoker for recipient until timelock.
+// hashlock is hex encoded sha256 of the secret preimage, timelock is unix time in seconds
+func (bt *Busy) LockHTLC(ctx contractapi.TransactionContextInterface, recipient string, token string, amount string, hashlock string, timelock uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	exists, err := ifTokenExists(ctx, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL001", response.Message)
+	}
+	if !exists || (strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL) {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+		logger.Error(response.Message)
+		return response, generateError(404, "LHTL002", response.Message)
+	}
+	bigAmount, ok := parseAmount(ctx, amount, token)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "LHTL003", response.Message)
+	}
+	hashlock = strings.ToLower(hashlock)
+	hashlockAsBytes, err := hex.DecodeString(hashlock)
+	if err != nil || len(hashlockAsBytes) != sha256.Size {
+		response.Message = "Hashlock has to be a hex encoded sha256 hash"
+		logger.Error(response.Message)
+		return response, generateError(412, "LHTL004", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if timelock <= uint64(now.Seconds) {
+		response.Message = "Timelock has to be in the future"
+		logger.Error(response.Message)
+		return response, generateError(412, "LHTL005", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	sender, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL006", response.Message)
+	}
+	if sender == recipient {
+		response.Message = "It is not possible to lock funds for your own address"
+		logger.Error(response.Message)
+		return response, generateError(409, "LHTL007", response.Message)
+	}
+	err = checkNewTokenOwner(ctx, recipient)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(412, "LHTL008", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, sender)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "LHTL009", response.Message)
+	}
+	err = checkTokenNotPaused(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "LHTL010", response.Message)
+	}
+
+	txFee, err := getTxFee(ctx, "htlc")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching transaction fee %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL011", response.Message)
+	}
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+
+	htlc := HTLC{
+		DocType:   "htlc",
+		ID:        response.TxID,
+		Sender:    sender,
+		Recipient: recipient,
+		Escrow:    getHTLCEscrowAddress(response.TxID),
+		Token:     token,
+		Amount:    bigAmount.String(),
+		Hashlock:  hashlock,
+		Timelock:  timelock,
+		Status:    HTLC_STATUS_LOCKED,
+		CreatedAt: uint64(now.Seconds),
+	}
+	err = transferHelper(ctx, sender, htlc.Escrow, bigAmount, token, bigTxFee, "htlc")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while locking funds: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "LHTL012", response.Message)
+	}
+	err = routeFee(ctx, "htlc", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL013", response.Message)
+	}
+	err = putHTLC(ctx, &htlc)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL014", response.Message)
+	}
+
+	userAddresses := []UserAddress{{Address: sender, Token: BUSY_COIN_SYMBOL}}
+	if token != BUSY_COIN_SYMBOL {
+		userAddresses = append(userAddresses, UserAddress{Address: sender, Token: token})
+	}
+	err = sendHTLCEvent(ctx, &htlc, userAddresses, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the htlc event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "LHTL015", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Funds have been successfully locked in htlc %s", htlc.ID)
+	response.Success = true
+	response.Data = htlc
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ClaimHTLC release locked funds to recipient of htlc, preimage is the hex encoded secret
+func (bt *Busy) ClaimHTLC(ctx contractapi.TransactionContextInterface, id string, preimage string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	htlc, err := getHTLC(ctx, id)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "CHTL001", response.Message)
+	}
+	if htlc.Status != HTLC_STATUS_LOCKED {
+		response.Message = fmt.Sprintf("Htlc %s has already been %s", id, htlc.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "CHTL002", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if uint64(now.Seconds) >= htlc.Timelock {
+		response.Message = fmt.Sprintf("Htlc %s has expired, it can only be refunded", id)
+		logger.Error(response.Message)
+		return response, generateError(410, "CHTL003", response.Message)
+	}
+	preimageAsBytes, err := hex.DecodeString(preimage)
+	if err != nil {
+		response.Message = "Preimage has to be hex encoded"
+		logger.Error(response.Message)
+		return response, generateError(412, "CHTL004", response.Message)
+	}
+	hash := sha256.Sum256(preimageAsBytes)
+	if hex.EncodeToString(hash[:]) != htlc.Hashlock {
+		response.Message = "Preimage does not match the hashlock"
+		logger.Error(response.Message)
+		return response, generateError(403, "CHTL005", response.Message)
+	}
+
+	bigAmount, _ := new(big.Int).SetString(htlc.Amount, 10)
+	err = transferHelper(ctx, htlc.Escrow, htlc.Recipient, bigAmount, htlc.Token, bigZero, "htlcClaim")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while releasing locked funds: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CHTL006", response.Message)
+	}
+	htlc.Status = HTLC_STATUS_CLAIMED
+	htlc.Preimage = strings.ToLower(preimage)
+	htlc.SettledAt = uint64(now.Seconds)
+	err = putHTLC(ctx, htlc)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CHTL007", response.Message)
+	}
+
+	err = sendHTLCEvent(ctx, htlc, []UserAddress{{Address: htlc.Recipient, Token: htlc.Token}}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the htlc event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CHTL008", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Htlc %s has been successfully claimed", id)
+	response.Success = true
+	response.Data = htlc
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RefundHTLC return locked funds to sender of htlc once timelock has passed
+func (bt *Busy) RefundHTLC(ctx contractapi.TransactionContextInterface, id string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	htlc, err := getHTLC(ctx, id)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "RHTL001", response.Message)
+	}
+	if htlc.Status != HTLC_STATUS_LOCKED {
+		response.Message = fmt.Sprintf("Htlc %s has already been %s", id, htlc.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "RHTL002", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if uint64(now.Seconds) < htlc.Timelock {
+		response.Message = fmt.Sprintf("Htlc %s can not be refunded before its timelock", id)
+		logger.Error(response.Message)
+		return response, generateError(425, "RHTL003", response.Message)
+	}
+
+	bigAmount, _ := new(big.Int).SetString(htlc.Amount, 10)
+	err = transferHelper(ctx, htlc.Escrow, htlc.Sender, bigAmount, htlc.Token, bigZero, "htlcRefund")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while refunding locked funds: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RHTL004", response.Message)
+	}
+	htlc.Status = HTLC_STATUS_REFUNDED
+	htlc.SettledAt = uint64(now.Seconds)
+	err = putHTLC(ctx, htlc)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RHTL005", response.Message)
+	}
+
+	err = sendHTLCEvent(ctx, htlc, []UserAddress{{Address: htlc.Sender, Token: htlc.Token}}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the htlc event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RHTL006", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Htlc %s has been successfully refunded", id)
+	response.Success = true
+	response.Data = htlc
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetHTLC get htlc details
+func (bt *Busy) GetHTLC(ctx contractapi.TransactionContextInterface, id string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	htlc, err := getHTLC(ctx, id)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GHTL001", response.Message)
+	}
+
+	response.Message = "Htlc has been successfully fetched"
+	response.Success = true
+	response.Data = htlc
+	return response, nil
+}
+
+// getHTLCEscrowAddress every htlc has its own escrow address so that settling one never touches funds of another
+func getHTLCEscrowAddress(id string) string {
+	return "htlc-" + id
+}
+
+func getHTLC(ctx contractapi.TransactionContextInterface, id string) (*HTLC, error) {
+	htlcAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("htlc~%s", id))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching htlc: %s", err.Error())
+	}
+	if htlcAsBytes == nil {
+		return nil, fmt.Errorf("htlc %s does not exist", id)
+	}
+	htlc := &HTLC{}
+	if err := json.Unmarshal(htlcAsBytes, htlc); err != nil {
+		return nil, fmt.Errorf("error while retrieving htlc: %s", err.Error())
+	}
+	return htlc, nil
+}
+
+func putHTLC(ctx contractapi.TransactionContextInterface, htlc *HTLC) error {
+	htlcAsBytes, _ := json.Marshal(htlc)
+	return ctx.GetStub().PutState(fmt.Sprintf("htlc~%s", htlc.ID), htlcAsBytes)
+}
+
+func sendHTLCEvent(ctx contractapi.TransactionContextInterface, htlc *HTLC, userAddresses []UserAddress, fee *big.Int) error {
+	htlcData := HTLCEvent{
+		HTLC:           *htlc,
+		UserAddresses:  userAddresses,
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	htlcAsBytes, _ := json.Marshal(htlcData)
+	return ctx.GetStub().SetEvent(HTLC_EVENT, htlcAsBytes)
+}
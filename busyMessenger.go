@@ -91,6 +91,13 @@ func (bm *BusyMessenger) SendMessage(ctx contractapi.TransactionContextInterface
 
 	logger.Info("Recieved a message from", senderDetails.DefaultWallet, "to", recipient)
 
+	err = checkWalletNotFrozen(ctx, senderDetails.DefaultWallet, recipient)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "SME024", response.Message)
+	}
+
 	// getting the default config for messaging functionality
 	configAsBytes, err := ctx.GetStub().GetState("MessageConfig")
 	if err != nil {
@@ -368,18 +375,11 @@ func RemoveCoins(ctx contractapi.TransactionContextInterface, address string, co
 	minusOne, _ := new(big.Int).SetString("-1", 10)
 	bigTxFee, _ := new(big.Int).SetString(coins, 10)
 
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, minusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
-	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err := ctx.GetStub().PutState(fmt.Sprintf("message~%s~%s~%s", ctx.GetStub().GetTxID(), address, BUSY_COIN_SYMBOL), utxoAsBytes)
+	err := putUTXO(ctx, address, new(big.Int).Set(bigTxFee).Mul(bigTxFee, minusOne), BUSY_COIN_SYMBOL, "message")
 	if err != nil {
 		return err
 	}
-	return nil
+	return addHistory(ctx, address, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_OUT, "", bigTxFee, bigZero, "message")
 }
 
 // RemoveCoins is to move coins from default wallet to message store
@@ -387,16 +387,9 @@ func AddCoins(ctx contractapi.TransactionContextInterface, address string, coins
 	plusOne, _ := new(big.Int).SetString("1", 10)
 	bigTxFee, _ := new(big.Int).SetString(coins, 10)
 
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, plusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
-	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err := ctx.GetStub().PutState(fmt.Sprintf("message~%s~%s~%s", ctx.GetStub().GetTxID(), address, BUSY_COIN_SYMBOL), utxoAsBytes)
+	err := putUTXO(ctx, address, bigTxFee.Mul(bigTxFee, plusOne), BUSY_COIN_SYMBOL, "message")
 	if err != nil {
 		return err
 	}
-	return nil
+	return addHistory(ctx, address, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_IN, "", bigTxFee, bigZero, "message")
 }
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const MULTISIG_EVENT = "MULTISIG"
+
+const (
+	PROPOSAL_STATUS_PENDING  = "pending"
+	PROPOSAL_STATUS_EXECUTED = "executed"
+	PROPOSAL_STATUS_EXPIRED  = "expired"
+)
+
+const MAX_MULTISIG_OWNERS = 20
+
+// MAX_PROPOSAL_DURATION longest time in seconds a proposal can stay open
+const MAX_PROPOSAL_DURATION = 30 * 24 * 60 * 60
+
+// CreateMultisigWallet create wallet controlled by owners, any threshold of them has to approve a transfer out of it.
+// Owners are user ids and invoker has to be one of them
+func (bt *Busy) CreateMultisigWallet(ctx contractapi.TransactionContextInterface, owners []string, threshold uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	if len(owners) < 2 || len(owners) > MAX_MULTISIG_OWNERS {
+		response.Message = fmt.Sprintf("Number of owners has to be in range of 2-%d", MAX_MULTISIG_OWNERS)
+		logger.Error(response.Message)
+		return response, generateError(412, "CMSW001", response.Message)
+	}
+	if threshold == 0 || threshold > uint64(len(owners)) {
+		response.Message = fmt.Sprintf("Threshold has to be in range of 1-%d", len(owners))
+		logger.Error(response.Message)
+		return response, generateError(412, "CMSW002", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	isOwner := false
+	seen := map[string]bool{}
+	for _, owner := range owners {
+		if seen[owner] {
+			response.Message = fmt.Sprintf("Owner %s is listed more than once", owner)
+			logger.Error(response.Message)
+			return response, generateError(412, "CMSW003", response.Message)
+		}
+		seen[owner] = true
+		isOwner = isOwner || owner == commonName
+		userAsBytes, err := ctx.GetStub().GetState(owner)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while fetching user from blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "CMSW004", response.Message)
+		}
+		user := User{}
+		if userAsBytes != nil {
+			_ = json.Unmarshal(userAsBytes, &user)
+		}
+		if user.DocType != "user" {
+			response.Message = fmt.Sprintf("User %s does not exist", owner)
+			logger.Error(response.Message)
+			return response, generateError(404, "CMSW005", response.Message)
+		}
+	}
+	if !isOwner {
+		response.Message = "You have to be one of the owners"
+		logger.Error(response.Message)
+		return response, generateError(403, "CMSW006", response.Message)
+	}
+
+	creator, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CMSW007", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "multisigCreate")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, creator, bigTxFee, "multisigCreate")
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "CMSW008", response.Message)
+	}
+
+	// wallet is not a "wallet" document so no single owner can spend from it with Transfer
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	wallet := MultisigWallet{
+		DocType:   "multisigWallet",
+		Address:   "B-" + response.TxID,
+		Owners:    owners,
+		Threshold: threshold,
+		Creator:   commonName,
+		CreatedAt: uint64(now.Seconds),
+	}
+	walletAsBytes, _ := json.Marshal(wallet)
+	err = ctx.GetStub().PutState(wallet.Address, walletAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CMSW009", response.Message)
+	}
+	err = chargeFee(ctx, creator, bigTxFee, "multisigCreate")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CMSW010", response.Message)
+	}
+
+	err = sendMultisigEvent(ctx, wallet.Address, nil, []UserAddress{{Address: creator, Token: BUSY_COIN_SYMBOL}}, []NFTEventInfo{}, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the multisig event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CMSW011", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Multisig wallet %s has been successfully created", wallet.Address)
+	response.Success = true
+	response.Data = wallet
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ProposeTransfer propose transfer of amount of token out of multisig wallet, proposer approves it right away.
+// Token can be a utxo token or a BusyTokens symbol, expiresAt is unix time in seconds
+func (bt *Busy) ProposeTransfer(ctx contractapi.TransactionContextInterface, wallet string, recipient string, token string, amount string, expiresAt uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	multisigWallet, err := getMultisigWallet(ctx, wallet)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "PMSP001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if !isMultisigOwner(multisigWallet, commonName) {
+		response.Message = fmt.Sprintf("You are not an owner of multisig wallet %s", wallet)
+		logger.Error(response.Message)
+		return response, generateError(403, "PMSP002", response.Message)
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	kind, err := getSwapAssetKind(ctx, token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "PMSP003", response.Message)
+	}
+	bigAmount, ok := parseSwapAmount(ctx, kind, amount, token)
+	if !ok || bigAmount.Cmp(bigZero) != 1 {
+		response.Message = "Amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PMSP004", response.Message)
+	}
+	recipientAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PMSP005", response.Message)
+	}
+	if recipientAsBytes == nil || recipient == wallet {
+		response.Message = fmt.Sprintf("Recipient %s is invalid", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "PMSP006", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if expiresAt <= uint64(now.Seconds) || expiresAt > uint64(now.Seconds)+MAX_PROPOSAL_DURATION {
+		response.Message = fmt.Sprintf("Expiry has to be in the future and at most %d seconds from now", MAX_PROPOSAL_DURATION)
+		logger.Error(response.Message)
+		return response, generateError(412, "PMSP007", response.Message)
+	}
+
+	proposal := MultisigProposal{
+		DocType:   "multisigProposal",
+		ID:        response.TxID,
+		Wallet:    wallet,
+		Proposer:  commonName,
+		Recipient: recipient,
+		Token:     token,
+		Amount:    bigAmount.String(),
+		Approvals: []string{commonName},
+		Status:    PROPOSAL_STATUS_PENDING,
+		ExpiresAt: expiresAt,
+		CreatedAt: uint64(now.Seconds),
+	}
+	err = putMultisigProposal(ctx, &proposal)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PMSP008", response.Message)
+	}
+
+	err = sendMultisigEvent(ctx, wallet, &proposal, []UserAddress{}, []NFTEventInfo{}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the multisig event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PMSP009", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Transfer %s has been successfully proposed", proposal.ID)
+	response.Success = true
+	response.Data = proposal
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ApproveProposal approve pending proposal as one of the owners of its wallet
+func (bt *Busy) ApproveProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*Response, error) {
+	return updateProposalApproval(ctx, proposalId, true, "AMSP")
+}
+
+// RevokeApproval take back approval of pending proposal given earlier
+func (bt *Busy) RevokeApproval(ctx contractapi.TransactionContextInterface, proposalId string) (*Response, error) {
+	return updateProposalApproval(ctx, proposalId, false, "RMSP")
+}
+
+func updateProposalApproval(ctx contractapi.TransactionContextInterface, proposalId string, approve bool, errorPrefix string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	proposal, multisigWallet, err := getPendingProposal(ctx, proposalId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(409, errorPrefix+"001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if !isMultisigOwner(multisigWallet, commonName) {
+		response.Message = fmt.Sprintf("You are not an owner of multisig wallet %s", multisigWallet.Address)
+		logger.Error(response.Message)
+		return response, generateError(403, errorPrefix+"002", response.Message)
+	}
+
+	approvals := []string{}
+	approved := false
+	for _, approver := range proposal.Approvals {
+		if approver == commonName {
+			approved = true
+			continue
+		}
+		approvals = append(approvals, approver)
+	}
+	if approve == approved {
+		response.Message = fmt.Sprintf("Approval of proposal %s is already in requested state", proposalId)
+		logger.Error(response.Message)
+		return response, generateError(409, errorPrefix+"003", response.Message)
+	}
+	if approve {
+		approvals = append(approvals, commonName)
+	}
+	proposal.Approvals = approvals
+	err = putMultisigProposal(ctx, proposal)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"004", response.Message)
+	}
+
+	err = sendMultisigEvent(ctx, proposal.Wallet, proposal, []UserAddress{}, []NFTEventInfo{}, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the multisig event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Proposal %s has %d of %d required approvals", proposalId, len(proposal.Approvals), multisigWallet.Threshold)
+	response.Success = true
+	response.Data = proposal
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// ExecuteProposal execute approved proposal, fee is paid by the multisig wallet
+func (bt *Busy) ExecuteProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	proposal, multisigWallet, err := getPendingProposal(ctx, proposalId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(409, "EMSP001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if !isMultisigOwner(multisigWallet, commonName) {
+		response.Message = fmt.Sprintf("You are not an owner of multisig wallet %s", multisigWallet.Address)
+		logger.Error(response.Message)
+		return response, generateError(403, "EMSP002", response.Message)
+	}
+	if uint64(len(proposal.Approvals)) < multisigWallet.Threshold {
+		response.Message = fmt.Sprintf("Proposal %s has %d of %d required approvals", proposalId, len(proposal.Approvals), multisigWallet.Threshold)
+		logger.Error(response.Message)
+		return response, generateError(403, "EMSP003", response.Message)
+	}
+
+	kind, err := getSwapAssetKind(ctx, proposal.Token)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "EMSP004", response.Message)
+	}
+	bigAmount, _ := new(big.Int).SetString(proposal.Amount, 10)
+	txFee, _ := getTxFee(ctx, "multisigExecute")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, proposal.Wallet, bigTxFee, "multisigExecute", proposal.Token)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "Multisig wallet does not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "EMSP005", response.Message)
+	}
+	err = moveSwapAsset(ctx, kind, proposal.Wallet, proposal.Recipient, proposal.Token, bigAmount, bigTxFee, "multisigExecute")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while transferring funds: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "EMSP006", response.Message)
+	}
+	err = routeFee(ctx, "multisigExecute", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "EMSP007", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	proposal.Status = PROPOSAL_STATUS_EXECUTED
+	proposal.ExecutedBy = commonName
+	proposal.ExecutedAt = uint64(now.Seconds)
+	err = putMultisigProposal(ctx, proposal)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "EMSP008", response.Message)
+	}
+
+	userAddresses := []UserAddress{{Address: proposal.Wallet, Token: BUSY_COIN_SYMBOL}}
+	nftList := []NFTEventInfo{}
+	if kind == SWAP_ASSET_UTXO {
+		userAddresses = append(userAddresses, UserAddress{Address: proposal.Wallet, Token: proposal.Token}, UserAddress{Address: proposal.Recipient, Token: proposal.Token})
+	} else {
+		busyTokensInfo, _ := getBusyTokensInfo(ctx, proposal.Token)
+		nftList = append(nftList, NFTEventInfo{Account: proposal.Wallet, Symbol: proposal.Token, TokenType: busyTokensInfo.MetaData.Type}, NFTEventInfo{Account: proposal.Recipient, Symbol: proposal.Token, TokenType: busyTokensInfo.MetaData.Type})
+	}
+	err = sendMultisigEvent(ctx, proposal.Wallet, proposal, userAddresses, nftList, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the multisig event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "EMSP009", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Proposal %s has been successfully executed", proposalId)
+	response.Success = true
+	response.Data = proposal
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetMultisigWallet get owners and threshold of multisig wallet
+func (bt *Busy) GetMultisigWallet(ctx contractapi.TransactionContextInterface, address string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	multisigWallet, err := getMultisigWallet(ctx, address)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GMSW001", response.Message)
+	}
+
+	response.Message = "Multisig wallet has been successfully fetched"
+	response.Success = true
+	response.Data = multisigWallet
+	return response, nil
+}
+
+// GetMultisigProposal get proposal details, pending proposal past its expiry is reported as expired
+func (bt *Busy) GetMultisigProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	proposal, err := getMultisigProposal(ctx, proposalId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GMSP001", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if proposal.Status == PROPOSAL_STATUS_PENDING && uint64(now.Seconds) >= proposal.ExpiresAt {
+		proposal.Status = PROPOSAL_STATUS_EXPIRED
+	}
+
+	response.Message = "Multisig proposal has been successfully fetched"
+	response.Success = true
+	response.Data = proposal
+	return response, nil
+}
+
+func isMultisigOwner(multisigWallet *MultisigWallet, commonName string) bool {
+	for _, owner := range multisigWallet.Owners {
+		if owner == commonName {
+			return true
+		}
+	}
+	return false
+}
+
+// getPendingProposal proposal which can still be approved or executed together with its wallet
+func getPendingProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*MultisigProposal, *MultisigWallet, error) {
+	proposal, err := getMultisigProposal(ctx, proposalId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proposal.Status != PROPOSAL_STATUS_PENDING {
+		return nil, nil, fmt.Errorf("proposal %s has already been %s", proposalId, proposal.Status)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if uint64(now.Seconds) >= proposal.ExpiresAt {
+		return nil, nil, fmt.Errorf("proposal %s has expired", proposalId)
+	}
+	multisigWallet, err := getMultisigWallet(ctx, proposal.Wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proposal, multisigWallet, nil
+}
+
+func getMultisigWallet(ctx contractapi.TransactionContextInterface, address string) (*MultisigWallet, error) {
+	walletAsBytes, err := ctx.GetStub().GetState(address)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching wallet: %s", err.Error())
+	}
+	multisigWallet := &MultisigWallet{}
+	if walletAsBytes != nil {
+		_ = json.Unmarshal(walletAsBytes, multisigWallet)
+	}
+	if multisigWallet.DocType != "multisigWallet" {
+		return nil, fmt.Errorf("multisig wallet %s does not exist", address)
+	}
+	return multisigWallet, nil
+}
+
+func getMultisigProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*MultisigProposal, error) {
+	proposalAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("multisigProposal~%s", proposalId))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching proposal: %s", err.Error())
+	}
+	if proposalAsBytes == nil {
+		return nil, fmt.Errorf("proposal %s does not exist", proposalId)
+	}
+	proposal := &MultisigProposal{}
+	if err := json.Unmarshal(proposalAsBytes, proposal); err != nil {
+		return nil, fmt.Errorf("error while retrieving proposal: %s", err.Error())
+	}
+	return proposal, nil
+}
+
+func putMultisigProposal(ctx contractapi.TransactionContextInterface, proposal *MultisigProposal) error {
+	proposalAsBytes, _ := json.Marshal(proposal)
+	return ctx.GetStub().PutState(fmt.Sprintf("multisigProposal~%s", proposal.ID), proposalAsBytes)
+}
+
+func sendMultisigEvent(ctx contractapi.TransactionContextInterface, wallet string, proposal *MultisigProposal, userAddresses []UserAddress, nftList []NFTEventInfo, fee *big.Int) error {
+	multisigData := MultisigEvent{
+		Wallet:         wallet,
+		Proposal:       proposal,
+		UserAddresses:  userAddresses,
+		NFTList:        nftList,
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	multisigAsBytes, _ := json.Marshal(multisigData)
+	return ctx.GetStub().SetEvent(MULTISIG_EVENT, multisigAsBytes)
+}
@@ -104,15 +104,15 @@ func (s *BusyNFT) Mint(ctx contractapi.TransactionContextInterface, account stri
 		}
 	}
 
-	balance, _ := getBalanceHelper(ctx, account, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "busyNft")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, account, bigTxFee, "busyNft", nftName)
 	if balance.Cmp(bigTxFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to mint new NFT", account)
 		logger.Error(response.Message)
 		return response, generateError(402, "SMIN009", response.Message)
 	}
-	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, bigTxFee.String(), "busyNft")
+	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, bigTxFee.String(), "busyNft", nftName)
 	if err != nil {
 		response.Message = "Error while burning mint fee"
 		logger.Error(response.Message)
@@ -235,15 +235,22 @@ func (s *BusyNFT) Transfer(ctx contractapi.TransactionContextInterface, sender s
 		return response, generateError(403, "STRA010", response.Message)
 	}
 
-	balance, _ := getBalanceHelper(ctx, senderDefaultAddress, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	err = checkWalletNotFrozen(ctx, senderDefaultAddress, recipient)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "STRA014", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "busynftTransfer")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, senderDefaultAddress, bigTxFee, "busynftTransfer", nftName)
 	if balance.Cmp(bigTxFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to transfer NFT", senderDefaultAddress)
 		logger.Error(response.Message)
 		return response, generateError(402, "STRA011", response.Message)
 	}
-	err = txFeeHelper(ctx, senderDefaultAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer")
+	err = txFeeHelper(ctx, senderDefaultAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer", nftName)
 	if err != nil {
 		response.Message = "Error while burning transaction fee"
 		logger.Error(response.Message)
@@ -390,15 +397,15 @@ func (s *BusyNFT) UpdateNFTMetaData(ctx contractapi.TransactionContextInterface,
 		}
 	}
 
-	balance, _ := getBalanceHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "busynftTransfer")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, defaultWalletAddress, bigTxFee, "busynftTransfer", nftName)
 	if balance.Cmp(bigTxFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to transfer NFT", defaultWalletAddress)
 		logger.Error(response.Message)
 		return response, generateError(402, "USMD009", response.Message)
 	}
-	err = txFeeHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer")
+	err = txFeeHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer", nftName)
 	if err != nil {
 		response.Message = "Error while burning transaction fee"
 		logger.Error(response.Message)
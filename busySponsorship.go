@@ -0,0 +1,416 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// sponsorshipScopePrefix composite key prefix of sponsorship lookup index, scope is the filter a sponsorship is indexed by
+const sponsorshipScopePrefix = "scope~sponsorshipId"
+
+const (
+	SPONSORSHIP_SCOPE_ANY       = "any"
+	SPONSORSHIP_SCOPE_OPERATION = "operation:"
+	SPONSORSHIP_SCOPE_CONTRACT  = "contract:"
+	SPONSORSHIP_SCOPE_TOKEN     = "token:"
+)
+
+// BusySponsorship contract
+type BusySponsorship struct {
+	contractapi.Contract
+}
+
+// CreateSponsorship pay fees of other users from default wallet of invoker up to cap.
+// operations are fee schedule operation types, contracts are contract names and tokens are token symbols,
+// an empty list matches everything
+func (bs *BusySponsorship) CreateSponsorship(ctx contractapi.TransactionContextInterface, operations []string, contracts []string, tokens []string, spendingCap string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+
+	bigCap, ok := new(big.Int).SetString(spendingCap, 10)
+	if !ok || bigCap.Cmp(bigZero) != 1 {
+		response.Message = fmt.Sprintf("Invalid cap %s, it must be greater than zero", spendingCap)
+		logger.Error(response.Message)
+		return response, generateError(412, "CSPN001", response.Message)
+	}
+	if isDuplicate(operations) || isDuplicate(contracts) || isDuplicate(tokens) {
+		response.Message = "Operations, contracts and tokens must not contain duplicates"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSPN002", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	sponsor, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSPN003", response.Message)
+	}
+	err = checkWalletNotFrozen(ctx, sponsor)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "CSPN004", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	sponsorship := Sponsorship{
+		DocType:    "sponsorship",
+		ID:         response.TxID,
+		Sponsor:    sponsor,
+		UserID:     commonName,
+		Operations: operations,
+		Contracts:  contracts,
+		Tokens:     tokens,
+		Cap:        bigCap.String(),
+		Spent:      bigZero.String(),
+		Active:     true,
+		CreatedAt:  uint64(now.Seconds),
+	}
+	err = putSponsorship(ctx, &sponsorship)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSPN005", response.Message)
+	}
+	for _, scope := range getSponsorshipScopes(&sponsorship) {
+		scopeKey, _ := ctx.GetStub().CreateCompositeKey(sponsorshipScopePrefix, []string{scope, sponsorship.ID})
+		err = ctx.GetStub().PutState(scopeKey, []byte{0x00})
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "CSPN006", response.Message)
+		}
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Sponsorship %s has been successfully created", sponsorship.ID)
+	response.Success = true
+	response.Data = sponsorship
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// UpdateSponsorshipCap change spending cap of sponsorship, only sponsor can do it
+func (bs *BusySponsorship) UpdateSponsorshipCap(ctx contractapi.TransactionContextInterface, sponsorshipId string, spendingCap string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	bigCap, ok := new(big.Int).SetString(spendingCap, 10)
+	if !ok || bigCap.Cmp(bigZero) != 1 {
+		response.Message = fmt.Sprintf("Invalid cap %s, it must be greater than zero", spendingCap)
+		logger.Error(response.Message)
+		return response, generateError(412, "USPC001", response.Message)
+	}
+	sponsorship, err := getSponsorship(ctx, sponsorshipId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "USPC002", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	if sponsorship.UserID != commonName {
+		response.Message = "Only sponsor can update cap of the sponsorship"
+		logger.Error(response.Message)
+		return response, generateError(403, "USPC003", response.Message)
+	}
+	if !sponsorship.Active {
+		response.Message = fmt.Sprintf("Sponsorship %s has been revoked", sponsorshipId)
+		logger.Error(response.Message)
+		return response, generateError(409, "USPC004", response.Message)
+	}
+
+	sponsorship.Cap = bigCap.String()
+	err = putSponsorship(ctx, sponsorship)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "USPC005", response.Message)
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Cap of sponsorship %s has been successfully updated", sponsorshipId)
+	response.Success = true
+	response.Data = sponsorship
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RevokeSponsorship stop paying fees, sponsor or busy network admin can revoke a sponsorship
+func (bs *BusySponsorship) RevokeSponsorship(ctx contractapi.TransactionContextInterface, sponsorshipId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	sponsorship, err := getSponsorship(ctx, sponsorshipId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "RSPN001", response.Message)
+	}
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	isAdmin := mspid == "BusyMSP" && commonName == "busy_network"
+	if sponsorship.UserID != commonName && !isAdmin {
+		response.Message = "You are not allowed to revoke the sponsorship"
+		logger.Error(response.Message)
+		return response, generateError(403, "RSPN002", response.Message)
+	}
+	if !sponsorship.Active {
+		response.Message = fmt.Sprintf("Sponsorship %s has already been revoked", sponsorshipId)
+		logger.Error(response.Message)
+		return response, generateError(409, "RSPN003", response.Message)
+	}
+
+	sponsorship.Active = false
+	err = putSponsorship(ctx, sponsorship)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RSPN004", response.Message)
+	}
+	for _, scope := range getSponsorshipScopes(sponsorship) {
+		scopeKey, _ := ctx.GetStub().CreateCompositeKey(sponsorshipScopePrefix, []string{scope, sponsorship.ID})
+		err = ctx.GetStub().DelState(scopeKey)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "RSPN005", response.Message)
+		}
+	}
+
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Sponsorship %s has been successfully revoked", sponsorshipId)
+	response.Success = true
+	response.Data = sponsorship
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetSponsorship get sponsorship details
+func (bs *BusySponsorship) GetSponsorship(ctx contractapi.TransactionContextInterface, sponsorshipId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	sponsorship, err := getSponsorship(ctx, sponsorshipId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GSPN001", response.Message)
+	}
+
+	response.Message = "Sponsorship has been successfully fetched"
+	response.Success = true
+	response.Data = sponsorship
+	return response, nil
+}
+
+func getSponsorship(ctx contractapi.TransactionContextInterface, sponsorshipId string) (*Sponsorship, error) {
+	sponsorshipAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("sponsorship~%s", sponsorshipId))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching sponsorship: %s", err.Error())
+	}
+	if sponsorshipAsBytes == nil {
+		return nil, fmt.Errorf("sponsorship %s does not exist", sponsorshipId)
+	}
+	sponsorship := &Sponsorship{}
+	if err := json.Unmarshal(sponsorshipAsBytes, sponsorship); err != nil {
+		return nil, fmt.Errorf("error while retrieving sponsorship: %s", err.Error())
+	}
+	return sponsorship, nil
+}
+
+func putSponsorship(ctx contractapi.TransactionContextInterface, sponsorship *Sponsorship) error {
+	sponsorshipAsBytes, _ := json.Marshal(sponsorship)
+	return ctx.GetStub().PutState(fmt.Sprintf("sponsorship~%s", sponsorship.ID), sponsorshipAsBytes)
+}
+
+// getSponsorshipScopes index entries of sponsorship, it is indexed by its most selective filter only
+// as a matching sponsorship has to match all of its filters anyway
+func getSponsorshipScopes(sponsorship *Sponsorship) []string {
+	scopes := []string{}
+	if len(sponsorship.Tokens) > 0 {
+		for _, token := range sponsorship.Tokens {
+			scopes = append(scopes, SPONSORSHIP_SCOPE_TOKEN+token)
+		}
+	} else if len(sponsorship.Operations) > 0 {
+		for _, operation := range sponsorship.Operations {
+			scopes = append(scopes, SPONSORSHIP_SCOPE_OPERATION+operation)
+		}
+	} else if len(sponsorship.Contracts) > 0 {
+		for _, contract := range sponsorship.Contracts {
+			scopes = append(scopes, SPONSORSHIP_SCOPE_CONTRACT+contract)
+		}
+	} else {
+		scopes = append(scopes, SPONSORSHIP_SCOPE_ANY)
+	}
+	return scopes
+}
+
+// sponsorshipApplies check if every filter of sponsorship matches the operation
+func sponsorshipApplies(sponsorship *Sponsorship, txType string, contract string, tokens []string) bool {
+	if len(sponsorship.Operations) > 0 && !contains(sponsorship.Operations, txType) {
+		return false
+	}
+	if len(sponsorship.Contracts) > 0 && !contains(sponsorship.Contracts, contract) {
+		return false
+	}
+	if len(sponsorship.Tokens) > 0 {
+		if len(tokens) == 0 {
+			return false
+		}
+		for _, token := range tokens {
+			if !contains(sponsorship.Tokens, token) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// findSponsorship first active sponsorship which can pay fee of txType for beneficiary, nil when there is none.
+// Only one sponsored fee per transaction is supported as spent amount written earlier in the same transaction is not visible.
+func findSponsorship(ctx contractapi.TransactionContextInterface, beneficiary string, fee *big.Int, txType string, tokens []string) (*Sponsorship, error) {
+	if fee.Cmp(bigZero) != 1 {
+		return nil, nil
+	}
+	contract := getContractName(ctx)
+	scopes := []string{}
+	for _, token := range tokens {
+		scopes = append(scopes, SPONSORSHIP_SCOPE_TOKEN+token)
+	}
+	scopes = append(scopes, SPONSORSHIP_SCOPE_OPERATION+txType, SPONSORSHIP_SCOPE_CONTRACT+contract, SPONSORSHIP_SCOPE_ANY)
+
+	checked := map[string]bool{}
+	for _, scope := range scopes {
+		resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(sponsorshipScopePrefix, []string{scope})
+		if err != nil {
+			return nil, fmt.Errorf("error while fetching sponsorships: %s", err.Error())
+		}
+		for resultIterator.HasNext() {
+			data, err := resultIterator.Next()
+			if err != nil {
+				resultIterator.Close()
+				return nil, fmt.Errorf("error while fetching sponsorships: %s", err.Error())
+			}
+			_, attributes, _ := ctx.GetStub().SplitCompositeKey(data.Key)
+			sponsorshipId := attributes[1]
+			if checked[sponsorshipId] {
+				continue
+			}
+			checked[sponsorshipId] = true
+
+			sponsorship, err := getSponsorship(ctx, sponsorshipId)
+			if err != nil {
+				resultIterator.Close()
+				return nil, err
+			}
+			if !sponsorship.Active || sponsorship.Sponsor == beneficiary || !sponsorshipApplies(sponsorship, txType, contract, tokens) {
+				continue
+			}
+			spent, _ := new(big.Int).SetString(sponsorship.Spent, 10)
+			bigCap, _ := new(big.Int).SetString(sponsorship.Cap, 10)
+			if new(big.Int).Add(spent, fee).Cmp(bigCap) == 1 {
+				continue
+			}
+			if checkWalletNotFrozen(ctx, sponsorship.Sponsor) != nil {
+				continue
+			}
+			balance, _ := getBalanceHelper(ctx, sponsorship.Sponsor, BUSY_COIN_SYMBOL)
+			if balance.Cmp(fee) == -1 {
+				continue
+			}
+			resultIterator.Close()
+			return sponsorship, nil
+		}
+		resultIterator.Close()
+	}
+	return nil, nil
+}
+
+// getFeePayerBalance BUSY balance of wallet which pays fee of txType for address, sponsor wallet when a sponsorship applies
+func getFeePayerBalance(ctx contractapi.TransactionContextInterface, address string, fee *big.Int, txType string, tokens ...string) *big.Int {
+	sponsorship, _ := findSponsorship(ctx, address, fee, txType, tokens)
+	if sponsorship != nil {
+		balance, _ := getBalanceHelper(ctx, sponsorship.Sponsor, BUSY_COIN_SYMBOL)
+		return balance
+	}
+	balance, _ := getBalanceHelper(ctx, address, BUSY_COIN_SYMBOL)
+	return balance
+}
+
+// chargeSponsor deduct fee paid for beneficiary from sponsor and add it to spent amount, fee still has to be routed
+func chargeSponsor(ctx contractapi.TransactionContextInterface, sponsorship *Sponsorship, beneficiary string, fee *big.Int, txType string) error {
+	err := putUTXO(ctx, sponsorship.Sponsor, new(big.Int).Mul(fee, minusOne), BUSY_COIN_SYMBOL, "sponsor~"+txType)
+	if err != nil {
+		return err
+	}
+	err = addHistory(ctx, sponsorship.Sponsor, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_OUT, beneficiary, bigZero, fee, "sponsor~"+txType)
+	if err != nil {
+		return err
+	}
+	spent, _ := new(big.Int).SetString(sponsorship.Spent, 10)
+	sponsorship.Spent = spent.Add(spent, fee).String()
+	return putSponsorship(ctx, sponsorship)
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const STREAM_EVENT = "STREAM"
+
+const (
+	STREAM_STATUS_ACTIVE    = "active"
+	STREAM_STATUS_COMPLETED = "completed"
+	STREAM_STATUS_CANCELLED = "cancelled"
+)
+
+// CreateStream escrow total amount of token from default wallet of invoker and stream it to recipient
+// linearly every second between start and end, both unix time in seconds
+func (bt *Busy) CreateStream(ctx contractapi.TransactionContextInterface, recipient string, token string, total string, start uint64, end uint64) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+
+	if token == "" {
+		token = BUSY_COIN_SYMBOL
+	}
+	exists, err := ifTokenExists(ctx, token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while fetching token details: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR001", response.Message)
+	}
+	if !exists || (strings.ToUpper(token) == BUSY_COIN_SYMBOL && token != BUSY_COIN_SYMBOL) {
+		response.Message = fmt.Sprintf("Symbol %s does not exist", token)
+		logger.Error(response.Message)
+		return response, generateError(404, "CSTR002", response.Message)
+	}
+	bigTotal, ok := parseAmount(ctx, total, token)
+	if !ok || bigTotal.Cmp(bigZero) != 1 {
+		response.Message = "Total amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSTR003", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	if end <= start || end <= uint64(now.Seconds) {
+		response.Message = "End of stream has to be after its start and in the future"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSTR004", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	sender, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR005", response.Message)
+	}
+	recipientAsBytes, err := ctx.GetStub().GetState(recipient)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR006", response.Message)
+	}
+	if recipientAsBytes == nil || recipient == sender {
+		response.Message = fmt.Sprintf("Recipient %s is invalid", recipient)
+		logger.Error(response.Message)
+		return response, generateError(404, "CSTR007", response.Message)
+	}
+
+	stream := Stream{
+		DocType:         "stream",
+		ID:              response.TxID,
+		Sender:          sender,
+		Recipient:       recipient,
+		Token:           token,
+		TotalAmount:     bigTotal.String(),
+		WithdrawnAmount: bigZero.String(),
+		StartAt:         start,
+		EndAt:           end,
+		Escrow:          getStreamEscrowAddress(response.TxID),
+		Status:          STREAM_STATUS_ACTIVE,
+		CreatedAt:       uint64(now.Seconds),
+	}
+
+	txFee, _ := getTxFee(ctx, "createStream")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	err = transferHelper(ctx, sender, stream.Escrow, bigTotal, token, bigTxFee, "createStream")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while escrowing stream funds: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "CSTR008", response.Message)
+	}
+	err = routeFee(ctx, "createStream", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR009", response.Message)
+	}
+	err = putStream(ctx, &stream)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR010", response.Message)
+	}
+
+	err = sendStreamEvent(ctx, &stream, sender, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the stream event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSTR011", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Stream %s has been successfully created", stream.ID)
+	response.Success = true
+	response.Data = stream
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// WithdrawFromStream withdraw everything streamed to recipient so far, only recipient can do it
+func (bt *Busy) WithdrawFromStream(ctx contractapi.TransactionContextInterface, streamId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	stream, err := getStream(ctx, streamId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "WSTR001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	_, err = resolveSenderWallet(ctx, commonName, stream.Recipient)
+	if err != nil {
+		response.Message = "Only recipient can withdraw from the stream"
+		logger.Error(response.Message)
+		return response, generateError(403, "WSTR002", response.Message)
+	}
+	if stream.Status != STREAM_STATUS_ACTIVE {
+		response.Message = fmt.Sprintf("Stream %s is %s", streamId, stream.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "WSTR003", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	streamedAmount := getStreamedAmount(stream, uint64(now.Seconds))
+	withdrawnAmount, _ := new(big.Int).SetString(stream.WithdrawnAmount, 10)
+	amount := new(big.Int).Sub(streamedAmount, withdrawnAmount)
+	if amount.Cmp(bigZero) != 1 {
+		response.Message = "There is nothing to withdraw at this time"
+		logger.Error(response.Message)
+		return response, generateError(425, "WSTR004", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "withdrawStream")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, stream.Recipient, bigTxFee, "withdrawStream", stream.Token)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "WSTR005", response.Message)
+	}
+
+	err = transferHelper(ctx, stream.Escrow, stream.Recipient, amount, stream.Token, bigZero, "withdrawStream")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while withdrawing from stream: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "WSTR006", response.Message)
+	}
+	err = chargeFee(ctx, stream.Recipient, bigTxFee, "withdrawStream", stream.Token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "WSTR007", response.Message)
+	}
+
+	stream.WithdrawnAmount = streamedAmount.String()
+	if stream.WithdrawnAmount == stream.TotalAmount {
+		stream.Status = STREAM_STATUS_COMPLETED
+	}
+	err = putStream(ctx, stream)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "WSTR008", response.Message)
+	}
+
+	err = sendStreamEvent(ctx, stream, stream.Recipient, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the stream event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "WSTR009", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s %s has been successfully withdrawn from stream %s", amount.String(), stream.Token, streamId)
+	response.Success = true
+	response.Data = stream
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CancelStream stop stream, part streamed so far and not yet withdrawn goes to recipient and the rest back to sender.
+// Both sender and recipient can cancel it
+func (bt *Busy) CancelStream(ctx contractapi.TransactionContextInterface, streamId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	stream, err := getStream(ctx, streamId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "XSTR001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	invoker, err := resolveSenderWallet(ctx, commonName, stream.Sender)
+	if err != nil {
+		invoker, err = resolveSenderWallet(ctx, commonName, stream.Recipient)
+	}
+	if err != nil {
+		response.Message = "Only sender or recipient can cancel the stream"
+		logger.Error(response.Message)
+		return response, generateError(403, "XSTR002", response.Message)
+	}
+	if stream.Status != STREAM_STATUS_ACTIVE {
+		response.Message = fmt.Sprintf("Stream %s is %s", streamId, stream.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "XSTR003", response.Message)
+	}
+	txFee, _ := getTxFee(ctx, "cancelStream")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, invoker, bigTxFee, "cancelStream", stream.Token)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = "You do not have enough balance to pay the transaction fee"
+		logger.Error(response.Message)
+		return response, generateError(402, "XSTR004", response.Message)
+	}
+
+	// both parts leave the escrow in a single transfer as escrow can be debited only once
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	streamedAmount := getStreamedAmount(stream, uint64(now.Seconds))
+	withdrawnAmount, _ := new(big.Int).SetString(stream.WithdrawnAmount, 10)
+	totalAmount, _ := new(big.Int).SetString(stream.TotalAmount, 10)
+	recipientAmount := new(big.Int).Sub(streamedAmount, withdrawnAmount)
+	senderAmount := new(big.Int).Sub(totalAmount, streamedAmount)
+	err = multiTransferHelper(ctx, stream.Escrow, []string{stream.Recipient, stream.Sender}, []*big.Int{recipientAmount, senderAmount}, stream.Token, bigZero, "cancelStream")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while settling stream: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSTR005", response.Message)
+	}
+	err = chargeFee(ctx, invoker, bigTxFee, "cancelStream", stream.Token)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while charging tx fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSTR006", response.Message)
+	}
+
+	stream.WithdrawnAmount = streamedAmount.String()
+	stream.RefundedAmount = senderAmount.String()
+	stream.Status = STREAM_STATUS_CANCELLED
+	stream.CancelledAt = uint64(now.Seconds)
+	err = putStream(ctx, stream)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSTR007", response.Message)
+	}
+
+	err = sendStreamEvent(ctx, stream, invoker, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the stream event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSTR008", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Stream %s has been successfully cancelled", streamId)
+	response.Success = true
+	response.Data = stream
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetStream get stream details together with amount withdrawable right now
+func (bt *Busy) GetStream(ctx contractapi.TransactionContextInterface, streamId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	stream, err := getStream(ctx, streamId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GSTR001", response.Message)
+	}
+	withdrawableAmount := new(big.Int).Set(bigZero)
+	if stream.Status == STREAM_STATUS_ACTIVE {
+		now, _ := ctx.GetStub().GetTxTimestamp()
+		withdrawnAmount, _ := new(big.Int).SetString(stream.WithdrawnAmount, 10)
+		withdrawableAmount.Sub(getStreamedAmount(stream, uint64(now.Seconds)), withdrawnAmount)
+	}
+
+	response.Message = "Stream has been successfully fetched"
+	response.Success = true
+	response.Data = StreamInfo{
+		Stream:             *stream,
+		WithdrawableAmount: withdrawableAmount.String(),
+	}
+	return response, nil
+}
+
+// getStreamedAmount part of stream released to recipient until now, same linear release as vesting
+func getStreamedAmount(stream *Stream, now uint64) *big.Int {
+	totalAmount, _ := new(big.Int).SetString(stream.TotalAmount, 10)
+	if now <= stream.StartAt {
+		return new(big.Int).Set(bigZero)
+	}
+	if now >= stream.EndAt {
+		return totalAmount
+	}
+	elapsed := new(big.Int).SetUint64(now - stream.StartAt)
+	duration := new(big.Int).SetUint64(stream.EndAt - stream.StartAt)
+	return totalAmount.Mul(elapsed, totalAmount).Div(totalAmount, duration)
+}
+
+// getStreamEscrowAddress every stream has its own escrow address so that settling one never touches funds of another
+func getStreamEscrowAddress(id string) string {
+	return "stream-" + id
+}
+
+func getStream(ctx contractapi.TransactionContextInterface, streamId string) (*Stream, error) {
+	streamAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("stream~%s", streamId))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching stream: %s", err.Error())
+	}
+	if streamAsBytes == nil {
+		return nil, fmt.Errorf("stream %s does not exist", streamId)
+	}
+	stream := &Stream{}
+	if err := json.Unmarshal(streamAsBytes, stream); err != nil {
+		return nil, fmt.Errorf("error while retrieving stream: %s", err.Error())
+	}
+	return stream, nil
+}
+
+func putStream(ctx contractapi.TransactionContextInterface, stream *Stream) error {
+	streamAsBytes, _ := json.Marshal(stream)
+	return ctx.GetStub().PutState(fmt.Sprintf("stream~%s", stream.ID), streamAsBytes)
+}
+
+func sendStreamEvent(ctx contractapi.TransactionContextInterface, stream *Stream, feePayer string, fee *big.Int) error {
+	streamData := StreamEvent{
+		Stream: *stream,
+		UserAddresses: []UserAddress{
+			{Address: feePayer, Token: BUSY_COIN_SYMBOL},
+			{Address: stream.Sender, Token: stream.Token},
+			{Address: stream.Recipient, Token: stream.Token},
+		},
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	streamAsBytes, _ := json.Marshal(streamData)
+	return ctx.GetStub().SetEvent(STREAM_EVENT, streamAsBytes)
+}
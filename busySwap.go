@@ -0,0 +1,460 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const SWAP_EVENT = "SWAP"
+
+const (
+	SWAP_STATUS_OPEN      = "open"
+	SWAP_STATUS_ACCEPTED  = "accepted"
+	SWAP_STATUS_CANCELLED = "cancelled"
+)
+
+// swap asset kinds, BUSY and BUSY20 tokens are held in utxos and NFT/GAME tokens in BusyTokens balances
+const (
+	SWAP_ASSET_UTXO        = "utxo"
+	SWAP_ASSET_BUSY_TOKENS = "busyTokens"
+)
+
+// CreateSwapOffer offer offerAmount of offerToken from default wallet of invoker for wantAmount of wantToken.
+// Offered side is held in escrow until the offer is accepted or cancelled, taker restricts who can accept it and empty taker allows anyone
+func (s *BusyTokens) CreateSwapOffer(ctx contractapi.TransactionContextInterface, offerToken string, offerAmount string, wantToken string, wantAmount string, taker string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	if offerToken == wantToken {
+		response.Message = "Offered and wanted tokens must be different"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSWP001", response.Message)
+	}
+	offerKind, err := getSwapAssetKind(ctx, offerToken)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "CSWP002", response.Message)
+	}
+	wantKind, err := getSwapAssetKind(ctx, wantToken)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "CSWP002", response.Message)
+	}
+	bigOfferAmount, ok := parseSwapAmount(ctx, offerKind, offerAmount, offerToken)
+	if !ok || bigOfferAmount.Cmp(bigZero) != 1 {
+		response.Message = "Offered amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSWP003", response.Message)
+	}
+	bigWantAmount, ok := parseSwapAmount(ctx, wantKind, wantAmount, wantToken)
+	if !ok || bigWantAmount.Cmp(bigZero) != 1 {
+		response.Message = "Wanted amount has to be a number greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "CSWP004", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	maker, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSWP005", response.Message)
+	}
+	if taker != "" {
+		if taker == maker {
+			response.Message = "It is not possible to make an offer to yourself"
+			logger.Error(response.Message)
+			return response, generateError(409, "CSWP006", response.Message)
+		}
+		err = checkNewTokenOwner(ctx, taker)
+		if err != nil {
+			response.Message = err.Error()
+			logger.Error(response.Message)
+			return response, generateError(412, "CSWP007", response.Message)
+		}
+	}
+
+	txFee, _ := getTxFee(ctx, "swapOffer")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, maker, bigTxFee, "swapOffer", offerToken)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = fmt.Sprintf("User %s does not have the enough balance to create the offer", maker)
+		logger.Error(response.Message)
+		return response, generateError(402, "CSWP008", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	offer := SwapOffer{
+		DocType:     "swapOffer",
+		ID:          response.TxID,
+		Maker:       maker,
+		Taker:       taker,
+		Escrow:      getSwapEscrowAddress(response.TxID),
+		OfferToken:  offerToken,
+		OfferAmount: bigOfferAmount.String(),
+		WantToken:   wantToken,
+		WantAmount:  bigWantAmount.String(),
+		Status:      SWAP_STATUS_OPEN,
+		CreatedAt:   uint64(now.Seconds),
+	}
+	err = moveSwapAsset(ctx, offerKind, maker, offer.Escrow, offerToken, bigOfferAmount, bigTxFee, "swapOffer")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while moving offered tokens to escrow: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "CSWP009", response.Message)
+	}
+	err = routeFee(ctx, "swapOffer", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSWP010", response.Message)
+	}
+	err = putSwapOffer(ctx, &offer)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSWP011", response.Message)
+	}
+
+	err = sendSwapEvent(ctx, &offer, []swapLeg{{offerKind, maker, offerToken}}, maker, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the swap event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "CSWP012", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Swap offer %s has been successfully created", offer.ID)
+	response.Success = true
+	response.Data = offer
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// AcceptSwapOffer pay wanted side of the offer to maker from default wallet of invoker and receive the escrowed side in the same transaction
+func (s *BusyTokens) AcceptSwapOffer(ctx contractapi.TransactionContextInterface, offerId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	offer, err := getSwapOffer(ctx, offerId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ASWP001", response.Message)
+	}
+	if offer.Status != SWAP_STATUS_OPEN {
+		response.Message = fmt.Sprintf("Swap offer %s has already been %s", offerId, offer.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "ASWP002", response.Message)
+	}
+
+	commonName, _ := getCommonName(ctx)
+	taker, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ASWP003", response.Message)
+	}
+	if taker == offer.Maker {
+		response.Message = "It is not possible to accept your own offer"
+		logger.Error(response.Message)
+		return response, generateError(409, "ASWP004", response.Message)
+	}
+	if offer.Taker != "" && offer.Taker != taker {
+		response.Message = fmt.Sprintf("Swap offer %s is reserved for another wallet", offerId)
+		logger.Error(response.Message)
+		return response, generateError(403, "ASWP005", response.Message)
+	}
+	offerKind, err := getSwapAssetKind(ctx, offer.OfferToken)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ASWP006", response.Message)
+	}
+	wantKind, err := getSwapAssetKind(ctx, offer.WantToken)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ASWP006", response.Message)
+	}
+
+	txFee, _ := getTxFee(ctx, "swapAccept")
+	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, taker, bigTxFee, "swapAccept", offer.WantToken)
+	if balance.Cmp(bigTxFee) == -1 {
+		response.Message = fmt.Sprintf("User %s does not have the enough balance to accept the offer", taker)
+		logger.Error(response.Message)
+		return response, generateError(402, "ASWP007", response.Message)
+	}
+
+	bigWantAmount, _ := new(big.Int).SetString(offer.WantAmount, 10)
+	err = moveSwapAsset(ctx, wantKind, taker, offer.Maker, offer.WantToken, bigWantAmount, bigTxFee, "swapAccept")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while paying wanted tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(402, "ASWP008", response.Message)
+	}
+	bigOfferAmount, _ := new(big.Int).SetString(offer.OfferAmount, 10)
+	err = moveSwapAsset(ctx, offerKind, offer.Escrow, taker, offer.OfferToken, bigOfferAmount, bigZero, "swapAccept")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while releasing offered tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ASWP009", response.Message)
+	}
+	err = routeFee(ctx, "swapAccept", bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while routing transaction fee: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ASWP010", response.Message)
+	}
+
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	offer.Status = SWAP_STATUS_ACCEPTED
+	offer.AcceptedBy = taker
+	offer.SettledAt = uint64(now.Seconds)
+	err = putSwapOffer(ctx, offer)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ASWP011", response.Message)
+	}
+
+	legs := []swapLeg{{wantKind, taker, offer.WantToken}, {wantKind, offer.Maker, offer.WantToken}, {offerKind, taker, offer.OfferToken}}
+	err = sendSwapEvent(ctx, offer, legs, taker, bigTxFee)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the swap event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ASWP012", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Swap offer %s has been successfully accepted", offerId)
+	response.Success = true
+	response.Data = offer
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// CancelSwapOffer return escrowed side of open offer to maker, only maker can cancel it
+func (s *BusyTokens) CancelSwapOffer(ctx contractapi.TransactionContextInterface, offerId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	offer, err := getSwapOffer(ctx, offerId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "XSWP001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	_, err = resolveSenderWallet(ctx, commonName, offer.Maker)
+	if err != nil {
+		response.Message = "Only maker can cancel the swap offer"
+		logger.Error(response.Message)
+		return response, generateError(403, "XSWP002", response.Message)
+	}
+	if offer.Status != SWAP_STATUS_OPEN {
+		response.Message = fmt.Sprintf("Swap offer %s has already been %s", offerId, offer.Status)
+		logger.Error(response.Message)
+		return response, generateError(409, "XSWP003", response.Message)
+	}
+	offerKind, err := getSwapAssetKind(ctx, offer.OfferToken)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "XSWP004", response.Message)
+	}
+
+	bigOfferAmount, _ := new(big.Int).SetString(offer.OfferAmount, 10)
+	err = moveSwapAsset(ctx, offerKind, offer.Escrow, offer.Maker, offer.OfferToken, bigOfferAmount, bigZero, "swapCancel")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while refunding offered tokens: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSWP005", response.Message)
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	offer.Status = SWAP_STATUS_CANCELLED
+	offer.SettledAt = uint64(now.Seconds)
+	err = putSwapOffer(ctx, offer)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSWP006", response.Message)
+	}
+
+	err = sendSwapEvent(ctx, offer, []swapLeg{{offerKind, offer.Maker, offer.OfferToken}}, offer.Maker, bigZero)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the swap event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "XSWP007", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Swap offer %s has been successfully cancelled", offerId)
+	response.Success = true
+	response.Data = offer
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// GetSwapOffer get swap offer details
+func (s *BusyTokens) GetSwapOffer(ctx contractapi.TransactionContextInterface, offerId string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	offer, err := getSwapOffer(ctx, offerId)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "GSWP001", response.Message)
+	}
+
+	response.Message = "Swap offer has been successfully fetched"
+	response.Success = true
+	response.Data = offer
+	return response, nil
+}
+
+// swapLeg balance of account changed by a swap
+type swapLeg struct {
+	kind    string
+	account string
+	symbol  string
+}
+
+// getSwapAssetKind BUSY and BUSY20 symbols are utxo assets, NFT/GAME symbols are BusyTokens assets
+func getSwapAssetKind(ctx contractapi.TransactionContextInterface, symbol string) (string, error) {
+	exists, err := ifTokenExists(ctx, symbol)
+	if err != nil {
+		return "", fmt.Errorf("error while fetching token details: %s", err.Error())
+	}
+	if exists {
+		return SWAP_ASSET_UTXO, nil
+	}
+	if _, err := getBusyTokensInfo(ctx, symbol); err != nil {
+		return "", fmt.Errorf("symbol %s does not exist", symbol)
+	}
+	return SWAP_ASSET_BUSY_TOKENS, nil
+}
+
+func parseSwapAmount(ctx contractapi.TransactionContextInterface, kind string, amount string, symbol string) (*big.Int, bool) {
+	if kind == SWAP_ASSET_UTXO {
+		return parseAmount(ctx, amount, symbol)
+	}
+	return new(big.Int).SetString(amount, 10)
+}
+
+// moveSwapAsset move amount of symbol and charge fee of txType from sender, routing the fee is left to the caller
+func moveSwapAsset(ctx contractapi.TransactionContextInterface, kind string, sender string, recipient string, symbol string, amount *big.Int, fee *big.Int, txType string) error {
+	if kind == SWAP_ASSET_UTXO {
+		return transferHelper(ctx, sender, recipient, amount, symbol, fee, txType)
+	}
+	// fee is only deducted here, same as transferHelper does for utxo assets
+	if fee.Cmp(bigZero) == 1 {
+		sponsorship, err := findSponsorship(ctx, sender, fee, txType, []string{symbol})
+		if err != nil {
+			return err
+		}
+		if sponsorship != nil {
+			err = chargeSponsor(ctx, sponsorship, sender, fee, txType)
+		} else {
+			err = debitFee(ctx, sender, fee, txType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err := removeBalance(ctx, sender, []string{symbol}, []*big.Int{amount})
+	if err != nil {
+		return err
+	}
+	return addBalance(ctx, sender, recipient, symbol, new(big.Int).Set(amount))
+}
+
+// getSwapEscrowAddress every offer has its own escrow address so that settling one never touches funds of another
+func getSwapEscrowAddress(id string) string {
+	return "swap-" + id
+}
+
+func getSwapOffer(ctx contractapi.TransactionContextInterface, offerId string) (*SwapOffer, error) {
+	offerAsBytes, err := ctx.GetStub().GetState(fmt.Sprintf("swapOffer~%s", offerId))
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching swap offer: %s", err.Error())
+	}
+	if offerAsBytes == nil {
+		return nil, fmt.Errorf("swap offer %s does not exist", offerId)
+	}
+	offer := &SwapOffer{}
+	if err := json.Unmarshal(offerAsBytes, offer); err != nil {
+		return nil, fmt.Errorf("error while retrieving swap offer: %s", err.Error())
+	}
+	return offer, nil
+}
+
+func putSwapOffer(ctx contractapi.TransactionContextInterface, offer *SwapOffer) error {
+	offerAsBytes, _ := json.Marshal(offer)
+	return ctx.GetStub().PutState(fmt.Sprintf("swapOffer~%s", offer.ID), offerAsBytes)
+}
+
+func sendSwapEvent(ctx contractapi.TransactionContextInterface, offer *SwapOffer, legs []swapLeg, feePayer string, fee *big.Int) error {
+	swapData := SwapEvent{
+		SwapOffer:      *offer,
+		UserAddresses:  []UserAddress{{Address: feePayer, Token: BUSY_COIN_SYMBOL}},
+		NFTList:        []NFTEventInfo{},
+		TransactionFee: fee.String(),
+		TransactionId:  ctx.GetStub().GetTxID(),
+	}
+	for _, leg := range legs {
+		if leg.kind == SWAP_ASSET_UTXO {
+			swapData.UserAddresses = append(swapData.UserAddresses, UserAddress{Address: leg.account, Token: leg.symbol})
+			continue
+		}
+		busyTokensInfo, err := getBusyTokensInfo(ctx, leg.symbol)
+		if err != nil {
+			return err
+		}
+		swapData.NFTList = append(swapData.NFTList, NFTEventInfo{
+			Account:   leg.account,
+			Symbol:    leg.symbol,
+			TokenType: busyTokensInfo.MetaData.Type,
+		})
+	}
+	swapAsBytes, _ := json.Marshal(swapData)
+	return ctx.GetStub().SetEvent(SWAP_EVENT, swapAsBytes)
+}
@@ -62,12 +62,14 @@ type TokenMetaData struct {
 
 // BusyTokensInfo holds metadata and owner info
 type BusyTokensInfo struct {
-	Account      string        `json:"account"`
-	CreatedAT    time.Time     `json:"created_at"`
-	TokenAddress string        `json:"tokenAddress"`
-	MetaData     TokenMetaData `json:"tokenMetadata"`
-	TotalSupply  string        `json:"totalSupply"`
-	TokenSymbol  string        `json:"tokenSymbol"`
+	Account        string        `json:"account"`
+	CreatedAT      time.Time     `json:"created_at"`
+	TokenAddress   string        `json:"tokenAddress"`
+	MetaData       TokenMetaData `json:"tokenMetadata"`
+	TotalSupply    string        `json:"totalSupply"`
+	TokenSymbol    string        `json:"tokenSymbol"`
+	PendingAccount string        `json:"pendingAccount,omitempty"`
+	Paused         bool          `json:"paused,omitempty"`
 }
 
 // NFTEvent Holds data for NFT event sent out
@@ -204,15 +206,15 @@ func (s *BusyTokens) Mint(ctx contractapi.TransactionContextInterface, account s
 		return response, generateError(500, "MIN014", response.Message)
 	}
 
-	balance, _ := getBalanceHelper(ctx, account, BUSY_COIN_SYMBOL)
 	mintFeeString, _ := getTokenIssueFeeForTokenType(ctx, metadata.Type)
 	mintFee, _ := new(big.Int).SetString(mintFeeString, 10)
+	balance := getFeePayerBalance(ctx, account, mintFee, "mint", symbol)
 	if balance.Cmp(mintFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to mint new tokens", account)
 		logger.Error(response.Message)
 		return response, generateError(402, "MIN015", response.Message)
 	}
-	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, mintFee.String(), "mint")
+	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, mintFee.String(), "mint", symbol)
 	if err != nil {
 		response.Message = "Error while burning mint transaction fee"
 		logger.Error(response.Message)
@@ -471,13 +473,13 @@ func (s *BusyTokens) MintBatch(ctx contractapi.TransactionContextInterface, acco
 			return response, generateError(500, "MIN021", response.Message)
 		}
 	}
-	balance, _ := getBalanceHelper(ctx, account, BUSY_COIN_SYMBOL)
+	balance := getFeePayerBalance(ctx, account, mintFeeBatch, "mintGame", symbols...)
 	if balance.Cmp(mintFeeBatch) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to mint enw tokens", account)
 		logger.Error(response.Message)
 		return response, generateError(402, "MIN015", response.Message)
 	}
-	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, mintFeeBatch.String(), "mintGame")
+	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, mintFeeBatch.String(), "mintGame", symbols...)
 	if err != nil {
 		response.Message = "Error while burning mint Transaction Fee"
 		logger.Error(response.Message)
@@ -534,6 +536,12 @@ func (s *BusyTokens) BurnBatch(ctx contractapi.TransactionContextInterface, acco
 		logger.Error(response.Message)
 		return response, generateError(403, "ATU001", response.Message)
 	}
+	err = checkBusyTokensNotPaused(ctx, symbols...)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "BRNB017", response.Message)
+	}
 
 	// Get Common Name of submitting client identity
 	commonName, err := getCommonName(ctx)
@@ -615,11 +623,11 @@ func (s *BusyTokens) BurnBatch(ctx contractapi.TransactionContextInterface, acco
 		return response, generateError(404, "BRNB013", response.Message)
 	}
 
-	balance, _ := getBalanceHelper(ctx, account, BUSY_COIN_SYMBOL)
-	burnFeeString, _ := getCurrentTxFee(ctx)
+	burnFeeString, _ := getTxFee(ctx, "mintGame")
 	burnFee, _ := new(big.Int).SetString(burnFeeString, 10)
 	numberofTokens := new(big.Int).SetInt64(int64(len(symbols)))
 	burnBatchFee := new(big.Int).Mul(burnFee, numberofTokens)
+	balance := getFeePayerBalance(ctx, account, burnBatchFee, "mintGame", symbols...)
 	if balance.Cmp(burnBatchFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to burn tokens", account)
 		logger.Error(response.Message)
@@ -633,7 +641,7 @@ func (s *BusyTokens) BurnBatch(ctx contractapi.TransactionContextInterface, acco
 		return response, generateError(500, "BRNB015", response.Message)
 	}
 
-	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, burnBatchFee.String(), "mintGame")
+	err = txFeeHelper(ctx, account, BUSY_COIN_SYMBOL, burnBatchFee.String(), "mintGame", symbols...)
 	if err != nil {
 		response.Message = "Error while burning mint transaction fee"
 		logger.Error(response.Message)
@@ -688,6 +696,12 @@ func (s *BusyTokens) TransferFrom(ctx contractapi.TransactionContextInterface, s
 		logger.Error(response.Message)
 		return response, generateError(403, "ATU001", response.Message)
 	}
+	err = checkBusyTokensNotPaused(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "NTRA016", response.Message)
+	}
 
 	// checking if the token already exists
 	tokenAddress := generateTokenAddress(symbol)
@@ -746,15 +760,15 @@ func (s *BusyTokens) TransferFrom(ctx contractapi.TransactionContextInterface, s
 		}
 	}
 
-	balance, _ := getBalanceHelper(ctx, sender, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "transfer")
 	transferFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, sender, transferFee, "transfer", symbol)
 	if balance.Cmp(transferFee) == -1 {
 		response.Message = fmt.Sprintf("You %s does not have the enough balance to tranfer tokens", sender)
 		logger.Error(response.Message)
 		return response, generateError(402, "NTRA011", response.Message)
 	}
-	err = txFeeHelper(ctx, sender, BUSY_COIN_SYMBOL, transferFee.String(), "transfer")
+	err = txFeeHelper(ctx, sender, BUSY_COIN_SYMBOL, transferFee.String(), "transfer", symbol)
 	if err != nil {
 		response.Message = "Error while burning transaction fee"
 		logger.Error(response.Message)
@@ -839,6 +853,12 @@ func (s *BusyTokens) BatchTransferFrom(ctx contractapi.TransactionContextInterfa
 		logger.Error(response.Message)
 		return response, generateError(403, "ATU001", response.Message)
 	}
+	err = checkBusyTokensNotPaused(ctx, symbols...)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(423, "NTRA016", response.Message)
+	}
 	// Get Common Name of submitting client identity
 	commonName, err := getCommonName(ctx)
 	if err != nil {
@@ -915,17 +935,17 @@ func (s *BusyTokens) BatchTransferFrom(ctx contractapi.TransactionContextInterfa
 
 	}
 
-	balance, _ := getBalanceHelper(ctx, sender, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "transferBatch")
 	transferFee, _ := new(big.Int).SetString(txFee, 10)
 	numberofTokens := new(big.Int).SetInt64(int64(len(symbols)))
 	transferFeeBatch := new(big.Int).Mul(transferFee, numberofTokens)
+	balance := getFeePayerBalance(ctx, sender, transferFeeBatch, "transferBatch", symbols...)
 	if balance.Cmp(transferFeeBatch) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to tranfer tokens", sender)
 		logger.Error(response.Message)
 		return response, generateError(402, "NTRA011", response.Message)
 	}
-	err = txFeeHelper(ctx, sender, BUSY_COIN_SYMBOL, transferFeeBatch.String(), "transferBatch")
+	err = txFeeHelper(ctx, sender, BUSY_COIN_SYMBOL, transferFeeBatch.String(), "transferBatch", symbols...)
 	if err != nil {
 		response.Message = "error while burning Transaction Fee"
 		logger.Error(response.Message)
@@ -1099,9 +1119,9 @@ func (s *BusyTokens) SetApprovalForAll(ctx contractapi.TransactionContextInterfa
 		return response, generateError(404, "SAPL005", response.Message)
 	}
 
-	balance, _ := getBalanceHelper(ctx, account, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "busynftTransfer")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, account, bigTxFee, "busynftTransfer")
 	if balance.Cmp(bigTxFee) == -1 {
 		response.Message = fmt.Sprintf("You %s do not have enough balance to set the approval for NFT/GAME tokens", account)
 		logger.Error(response.Message)
@@ -1398,15 +1418,15 @@ func (s *BusyTokens) UpdateTokenMetaData(ctx contractapi.TransactionContextInter
 		return response, generateError(500, "UTMD006", response.Message)
 	}
 
-	balance, _ := getBalanceHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL)
-	txFee, _ := getCurrentTxFee(ctx)
+	txFee, _ := getTxFee(ctx, "busynftTransfer")
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	balance := getFeePayerBalance(ctx, defaultWalletAddress, bigTxFee, "busynftTransfer", symbol)
 	if balance.Cmp(bigTxFee) == -1 {
 		response.Message = fmt.Sprintf("User %s does not have the enough balance to Update Metadata of NFT", defaultWalletAddress)
 		logger.Error(response.Message)
 		return response, generateError(412, "UTMD007", response.Message)
 	}
-	err = txFeeHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer")
+	err = txFeeHelper(ctx, defaultWalletAddress, BUSY_COIN_SYMBOL, bigTxFee.String(), "busynftTransfer", symbol)
 	if err != nil {
 		response.Message = "Error while burning Transaction Fee"
 		logger.Error(response.Message)
@@ -1553,6 +1573,267 @@ func addTotalSupplyTokensUTXO(ctx contractapi.TransactionContextInterface, token
 	return nil
 }
 
+// TransferTokenOwnership propose newOwner as owner of NFT/GAME token, it takes effect once newOwner accepts it.
+// Empty newOwner cancels pending proposal
+func (s *BusyTokens) TransferTokenOwnership(ctx contractapi.TransactionContextInterface, symbol string, newOwner string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	busyTokensInfo, err := getBusyTokensInfo(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "TTOW001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTOW002", response.Message)
+	}
+	if busyTokensInfo.Account == "" || busyTokensInfo.Account != defaultWalletAddress {
+		response.Message = fmt.Sprintf("The account %s is not the owner of %s", defaultWalletAddress, symbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "TTOW003", response.Message)
+	}
+	if newOwner == busyTokensInfo.Account {
+		response.Message = fmt.Sprintf("%s is already owner of %s", newOwner, symbol)
+		logger.Error(response.Message)
+		return response, generateError(409, "TTOW004", response.Message)
+	}
+	if newOwner != "" {
+		err = checkNewTokenOwner(ctx, newOwner)
+		if err != nil {
+			response.Message = err.Error()
+			logger.Error(response.Message)
+			return response, generateError(412, "TTOW005", response.Message)
+		}
+	}
+
+	busyTokensInfo.PendingAccount = newOwner
+	busyTokensInfoAsBytes, _ := json.Marshal(busyTokensInfo)
+	err = ctx.GetStub().PutState(generateTokenAddress(symbol), busyTokensInfoAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTOW006", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, busyTokensInfo.TokenSymbol, busyTokensInfo.Account, busyTokensInfo.Account, newOwner)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "TTOW007", response.Message)
+	}
+
+	if newOwner == "" {
+		response.Message = fmt.Sprintf("Pending ownership transfer of %s has been cancelled", symbol)
+	} else {
+		response.Message = fmt.Sprintf("Ownership of %s has been proposed to %s", symbol, newOwner)
+	}
+	response.Success = true
+	response.Data = busyTokensInfo
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// AcceptTokenOwnership accept ownership of NFT/GAME token proposed to one of invoker's wallets
+func (s *BusyTokens) AcceptTokenOwnership(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	busyTokensInfo, err := getBusyTokensInfo(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "ATOW001", response.Message)
+	}
+	if busyTokensInfo.PendingAccount == "" {
+		response.Message = fmt.Sprintf("There is no pending ownership transfer for %s", symbol)
+		logger.Error(response.Message)
+		return response, generateError(409, "ATOW002", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	_, err = resolveSenderWallet(ctx, commonName, busyTokensInfo.PendingAccount)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(403, "ATOW003", response.Message)
+	}
+
+	previousOwner := busyTokensInfo.Account
+	busyTokensInfo.Account = busyTokensInfo.PendingAccount
+	busyTokensInfo.PendingAccount = ""
+	busyTokensInfoAsBytes, _ := json.Marshal(busyTokensInfo)
+	err = ctx.GetStub().PutState(generateTokenAddress(symbol), busyTokensInfoAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ATOW004", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, busyTokensInfo.TokenSymbol, previousOwner, busyTokensInfo.Account, "")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "ATOW005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("%s is now owner of %s", busyTokensInfo.Account, symbol)
+	response.Success = true
+	response.Data = busyTokensInfo
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// RenounceTokenOwnership give up ownership of NFT/GAME token, nobody can burn it or update its metadata afterwards
+func (s *BusyTokens) RenounceTokenOwnership(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	err := CheckCredentials(ctx, DEFAULT_CREDS, "true")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while validating credentials: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(403, "ATU001", response.Message)
+	}
+	busyTokensInfo, err := getBusyTokensInfo(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, "RTOW001", response.Message)
+	}
+	commonName, _ := getCommonName(ctx)
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTOW002", response.Message)
+	}
+	if busyTokensInfo.Account == "" || busyTokensInfo.Account != defaultWalletAddress {
+		response.Message = fmt.Sprintf("The account %s is not the owner of %s", defaultWalletAddress, symbol)
+		logger.Error(response.Message)
+		return response, generateError(403, "RTOW003", response.Message)
+	}
+
+	previousOwner := busyTokensInfo.Account
+	busyTokensInfo.Account = ""
+	busyTokensInfo.PendingAccount = ""
+	busyTokensInfoAsBytes, _ := json.Marshal(busyTokensInfo)
+	err = ctx.GetStub().PutState(generateTokenAddress(symbol), busyTokensInfoAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTOW004", response.Message)
+	}
+
+	err = sendOwnershipEvent(ctx, busyTokensInfo.TokenSymbol, previousOwner, "", "")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the ownership event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "RTOW005", response.Message)
+	}
+
+	response.Message = fmt.Sprintf("Ownership of %s has been renounced", symbol)
+	response.Success = true
+	response.Data = busyTokensInfo
+	logger.Info(response.Message)
+	return response, nil
+}
+
+// PauseToken stop every transfer and burn of NFT/GAME token, token owner or busy network admin can pause it
+func (s *BusyTokens) PauseToken(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	return updateBusyTokensPauseStatus(ctx, symbol, true, "PATK")
+}
+
+// UnpauseToken allow transfers and burns of paused NFT/GAME token again
+func (s *BusyTokens) UnpauseToken(ctx contractapi.TransactionContextInterface, symbol string) (*Response, error) {
+	return updateBusyTokensPauseStatus(ctx, symbol, false, "UPTK")
+}
+
+func updateBusyTokensPauseStatus(ctx contractapi.TransactionContextInterface, symbol string, paused bool, errorPrefix string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
+	}
+
+	busyTokensInfo, err := getBusyTokensInfo(ctx, symbol)
+	if err != nil {
+		response.Message = err.Error()
+		logger.Error(response.Message)
+		return response, generateError(404, errorPrefix+"001", response.Message)
+	}
+	allowed, err := isTokenAdminOrNetworkAdmin(ctx, busyTokensInfo.Account)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error occurred while fetching wallet %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"002", response.Message)
+	}
+	if !allowed {
+		response.Message = fmt.Sprintf("You are not allowed to update pause status of %s", symbol)
+		logger.Error(response.Message)
+		return response, generateError(403, errorPrefix+"003", response.Message)
+	}
+	if busyTokensInfo.Paused == paused {
+		response.Message = fmt.Sprintf("Token %s is already in the requested state", symbol)
+		logger.Error(response.Message)
+		return response, generateError(409, errorPrefix+"004", response.Message)
+	}
+
+	busyTokensInfo.Paused = paused
+	busyTokensInfoAsBytes, _ := json.Marshal(busyTokensInfo)
+	err = ctx.GetStub().PutState(generateTokenAddress(symbol), busyTokensInfoAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"005", response.Message)
+	}
+
+	err = sendPauseEvent(ctx, busyTokensInfo.TokenSymbol, paused)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while sending the pause event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, errorPrefix+"006", response.Message)
+	}
+
+	if paused {
+		response.Message = fmt.Sprintf("Token %s has been successfully paused", symbol)
+	} else {
+		response.Message = fmt.Sprintf("Token %s has been successfully unpaused", symbol)
+	}
+	response.Success = true
+	response.Data = busyTokensInfo
+	logger.Info(response.Message)
+	return response, nil
+}
+
 // GetTotalSupply get total supply of specified token
 func (s *BusyTokens) GetTotalSupplyNftBatch(ctx contractapi.TransactionContextInterface, symbols []string) (*Response, error) {
 	response := &Response{
@@ -1599,6 +1880,15 @@ func (s *BusyTokens) GetTotalSupplyNftBatch(ctx contractapi.TransactionContextIn
 }
 
 func removeBalance(ctx contractapi.TransactionContextInterface, sender string, symbols []string, amounts []*big.Int) error {
+	err := checkWalletNotFrozen(ctx, sender)
+	if err != nil {
+		return err
+	}
+	err = checkBusyTokensNotPaused(ctx, symbols...)
+	if err != nil {
+		return err
+	}
+
 	// Calculate the total amount of each token to withdraw
 	necessaryFunds := make(map[string]*big.Int) // token symbol -> necessary amount
 
@@ -1728,31 +2018,11 @@ func sortedKeys(m map[string]*big.Int) []string {
 	return keys
 }
 
-// txFeeHelper burns fee from the user and reduce total supply accordingly
-func txFeeHelper(ctx contractapi.TransactionContextInterface, address string, token string, txFee string, txType string) error {
-	minusOne, _ := new(big.Int).SetString("-1", 10)
+// txFeeHelper charges fee from the user and routes it to the destination configured for txType
+// tokens are the tokens operation works with, they are used to find a sponsorship paying the fee
+func txFeeHelper(ctx contractapi.TransactionContextInterface, address string, token string, txFee string, txType string, tokens ...string) error {
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
-	err := addTotalSupplyUTXO(ctx, token, new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee))
-	if err != nil {
-		return err
-	}
-
-	// err = addUTXO(ctx, address, bigTxFee, token)
-	// if err != nil {
-	// 	return err
-	// }
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, minusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
-	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err = ctx.GetStub().PutState(fmt.Sprintf("burnTxFee~%s~%s~%s~%s", ctx.GetStub().GetTxID(), txType, address, BUSY_COIN_SYMBOL), utxoAsBytes)
-	if err != nil {
-		return err
-	}
-	return nil
+	return chargeFee(ctx, address, bigTxFee, txType, tokens...)
 }
 
 // check if string is in slice
@@ -1766,6 +2036,72 @@ func contains(s []string, str string) bool {
 	return false
 }
 
+func getBusyTokensInfo(ctx contractapi.TransactionContextInterface, symbol string) (*BusyTokensInfo, error) {
+	busyTokensInfoAsBytes, err := ctx.GetStub().GetState(generateTokenAddress(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("error while getting state in blockchain: %s", err.Error())
+	}
+	if busyTokensInfoAsBytes == nil {
+		return nil, fmt.Errorf("token %s does not exist", symbol)
+	}
+	busyTokensInfo := BusyTokensInfo{}
+	if err := json.Unmarshal(busyTokensInfoAsBytes, &busyTokensInfo); err != nil {
+		return nil, fmt.Errorf("error while retrieving token info: %s", err.Error())
+	}
+	return &busyTokensInfo, nil
+}
+
+// getBusyTokensBalancesOfToken balance of every account holding NFT/GAME token, balances from all senders are summed up
+func getBusyTokensBalancesOfToken(ctx contractapi.TransactionContextInterface, symbol string) (map[string]*big.Int, error) {
+	balances := map[string]*big.Int{}
+	balanceIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(balancePrefix, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state for prefix %v: %v", balancePrefix, err)
+	}
+	defer balanceIterator.Close()
+	for balanceIterator.HasNext() {
+		queryResponse, err := balanceIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the next state for prefix %v: %v", balancePrefix, err)
+		}
+		_, compositeKeyParts, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil || len(compositeKeyParts) < 2 || compositeKeyParts[1] != symbol {
+			continue
+		}
+		bigAmount, ok := new(big.Int).SetString(string(queryResponse.Value), 10)
+		if !ok {
+			continue
+		}
+		account := compositeKeyParts[0]
+		if _, ok := balances[account]; !ok {
+			balances[account] = new(big.Int).Set(bigZero)
+		}
+		balances[account].Add(balances[account], bigAmount)
+	}
+	return balances, nil
+}
+
+// checkBusyTokensNotPaused returns error if any of the NFT/GAME tokens is paused, tokens which do not exist are left to the caller
+func checkBusyTokensNotPaused(ctx contractapi.TransactionContextInterface, symbols ...string) error {
+	for _, symbol := range symbols {
+		busyTokensInfoAsBytes, err := ctx.GetStub().GetState(generateTokenAddress(symbol))
+		if err != nil {
+			return fmt.Errorf("error while getting state in blockchain: %s", err.Error())
+		}
+		if busyTokensInfoAsBytes == nil {
+			continue
+		}
+		busyTokensInfo := BusyTokensInfo{}
+		if err := json.Unmarshal(busyTokensInfoAsBytes, &busyTokensInfo); err != nil {
+			return fmt.Errorf("error while retrieving token info: %s", err.Error())
+		}
+		if busyTokensInfo.Paused {
+			return fmt.Errorf("token %s is paused", symbol)
+		}
+	}
+	return nil
+}
+
 func generateTokenAddress(symbol string) string {
 	// symbol is case insensitive
 	symbol = strings.ToUpper(symbol)
@@ -15,6 +15,8 @@ type BusyVoting struct {
 	contractapi.Contract
 }
 
+const poolHistoryPrefix = "createdAt~poolId"
+
 func (bv *BusyVoting) CreatePool(ctx contractapi.TransactionContextInterface, walletid string, PoolName string, PoolDescription string, token string) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
@@ -95,7 +97,8 @@ func (bv *BusyVoting) CreatePool(ctx contractapi.TransactionContextInterface, wa
 		return response, generateError(402, "POOL009", response.Message)
 	}
 
-	err = burnCoins(ctx, defaultAddress, votingConfig.PoolFee, token)
+	poolFee, _ := new(big.Int).SetString(votingConfig.PoolFee, 10)
+	err = burnCoins(ctx, defaultAddress, poolFee, token, "createPool")
 
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while burning tokens at pool creation %s", err.Error())
@@ -208,22 +211,24 @@ func (bv *BusyVoting) CreateVote(ctx contractapi.TransactionContextInterface, wa
 		return response, generateError(405, "VOTE006", response.Message)
 	}
 
+	// walletid is the wallet to vote from, empty walletid means default wallet
 	commonName, _ := getCommonName(ctx)
-	defaultAddress, err := getDefaultWalletAddress(ctx, commonName)
+	defaultAddress, err := resolveSenderWallet(ctx, commonName, walletid)
 	if err != nil {
-		response.Message = "Error getting the default address"
+		response.Message = fmt.Sprintf("Walletid in the request does not belong to the user: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "VOTE007", response.Message)
+		return response, generateError(400, "VOTE008", response.Message)
 	}
-	if walletid != defaultAddress {
-		response.Message = "Walletid in the request does not match with default wallet id"
+	err = checkWalletNotFrozen(ctx, defaultAddress)
+	if err != nil {
+		response.Message = err.Error()
 		logger.Error(response.Message)
-		return response, generateError(400, "VOTE008", response.Message)
+		return response, generateError(423, "VOTE015", response.Message)
 	}
 
 	balance, _ := getBalanceHelper(ctx, defaultAddress, BUSY_COIN_SYMBOL)
 
-	amountInt, isConverted := new(big.Int).SetString(amount, 10)
+	amountInt, isConverted := parseAmount(ctx, amount, BUSY_COIN_SYMBOL)
 
 	if !isConverted {
 		response.Message = "Invalid Amount provided in the request"
@@ -242,7 +247,7 @@ func (bv *BusyVoting) CreateVote(ctx contractapi.TransactionContextInterface, wa
 		logger.Error(response.Message)
 		return response, generateError(402, "VOTE011", response.Message)
 	}
-	err = burnCoins(ctx, defaultAddress, amount, token)
+	err = burnCoins(ctx, defaultAddress, amountInt, token, "vote")
 
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while burning tokens at vote %s", err.Error())
@@ -264,7 +269,7 @@ func (bv *BusyVoting) CreateVote(ctx contractapi.TransactionContextInterface, wa
 		DocType:     "Vote",
 		VoteTime:    time.Now(),
 		VoteAddress: votingaddress,
-		Tokens:      amount,
+		Tokens:      amountInt.String(),
 		VoteType:    voteType,
 	}
 
@@ -352,19 +357,8 @@ func (bv *BusyVoting) DestroyPool(ctx contractapi.TransactionContextInterface) (
 		return response, generateError(400, "DPOL004", response.Message)
 	}
 
-	poolDataListAsBytes, err := ctx.GetStub().GetState("PoolDataList")
-	if err != nil {
-		response.Message = fmt.Sprintf("Error while retrieving the pool List: %s", err.Error())
-		logger.Error(response.Message)
-		return response, generateError(500, "DPOL005", response.Message)
-	}
-	poolDataList := []Pool{}
-	_ = json.Unmarshal(poolDataListAsBytes, &poolDataList)
-	// appending the current pool data
-	poolDataList = append(poolDataList, PoolData)
-	poolAddrListAsBytes, _ := json.Marshal(poolDataList)
-	// storing the data at PoolDataList
-	err = ctx.GetStub().PutState("PoolDataList", poolAddrListAsBytes)
+	// storing the data in pool history
+	err = addPoolHistory(ctx, PoolData)
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
 		logger.Error(response.Message)
@@ -427,53 +421,132 @@ func (bv *BusyVoting) QueryPool(ctx contractapi.TransactionContextInterface) (*R
 	return response, nil
 }
 
-// burnCoins is to burn some coins from user and reduce total supply accordingly for voting functionity
-func burnCoins(ctx contractapi.TransactionContextInterface, address string, coins string, token string) error {
-	minusOne, _ := new(big.Int).SetString("-1", 10)
-	bigTxFee, _ := new(big.Int).SetString(coins, 10)
-	err := addTotalSupplyUTXO(ctx, token, new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee))
+// burnCoins is to take coins from user for voting functionity, they are routed like any other fee
+func burnCoins(ctx contractapi.TransactionContextInterface, address string, coins *big.Int, token string, txType string) error {
+	err := debitFee(ctx, address, coins, txType)
 	if err != nil {
 		return err
 	}
+	return routeFee(ctx, txType, coins)
+}
 
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, minusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
+// Pool History to retrieve the List of pools created till date
+func (bv *BusyVoting) PoolHistory(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (*Response, error) {
+	response := &Response{
+		TxID:    ctx.GetStub().GetTxID(),
+		Success: false,
+		Message: "",
+		Data:    nil,
 	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err = ctx.GetStub().PutState(fmt.Sprintf("voting~%s~%s~%s", ctx.GetStub().GetTxID(), address, BUSY_COIN_SYMBOL), utxoAsBytes)
+
+	if pageSize <= 0 {
+		response.Message = "Page size has to be greater than zero"
+		logger.Error(response.Message)
+		return response, generateError(412, "PHIS002", response.Message)
+	}
+	resultIterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(poolHistoryPrefix, []string{}, pageSize, bookmark)
 	if err != nil {
-		return err
+		response.Message = fmt.Sprintf("Error while retrieving the pool List: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "PHIS001", response.Message)
+	}
+	defer resultIterator.Close()
+
+	poolDataList := []Pool{}
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while retrieving the pool List: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "PHIS001", response.Message)
+		}
+		pool := Pool{}
+		_ = json.Unmarshal(data.Value, &pool)
+		poolDataList = append(poolDataList, pool)
+	}
+
+	response.Success = true
+	response.Data = PagedResult{
+		Records:             poolDataList,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
 	}
-	return nil
+	response.Message = "Pool history data has been successfully fetched"
+	return response, nil
 }
 
-// Pool History to retrieve the List of pools created till date
-func (bv *BusyVoting) PoolHistory(ctx contractapi.TransactionContextInterface) (*Response, error) {
+// MigratePoolHistory move pools stored in the old PoolDataList to pool history keys
+func (bv *BusyVoting) MigratePoolHistory(ctx contractapi.TransactionContextInterface) (*Response, error) {
 	response := &Response{
 		TxID:    ctx.GetStub().GetTxID(),
 		Success: false,
 		Message: "",
 		Data:    nil,
 	}
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid != "BusyMSP" || commonName != "busy_network" {
+		response.Message = "You are not allowed to migrate pool history"
+		logger.Error(response.Message)
+		return response, generateError(403, "MPHS001", response.Message)
+	}
 
 	poolDataListAsBytes, err := ctx.GetStub().GetState("PoolDataList")
 	if err != nil {
 		response.Message = fmt.Sprintf("Error while retrieving the pool List: %s", err.Error())
 		logger.Error(response.Message)
-		return response, generateError(500, "PHIS001", response.Message)
+		return response, generateError(500, "MPHS002", response.Message)
+	}
+	if poolDataListAsBytes == nil {
+		response.Message = "Pool history has been already migrated"
+		logger.Error(response.Message)
+		return response, generateError(409, "MPHS003", response.Message)
 	}
 	poolDataList := []Pool{}
 	_ = json.Unmarshal(poolDataListAsBytes, &poolDataList)
+	for _, pool := range poolDataList {
+		err = addPoolHistory(ctx, pool)
+		if err != nil {
+			response.Message = fmt.Sprintf("Error while updating state in blockchain: %s", err.Error())
+			logger.Error(response.Message)
+			return response, generateError(500, "MPHS004", response.Message)
+		}
+	}
+	err = ctx.GetStub().DelState("PoolDataList")
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while deleting the pool List: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "MPHS005", response.Message)
+	}
 
+	balanceData := BalanceEvent{
+		UserAddresses:  []UserAddress{},
+		TransactionFee: bigZero.String(),
+		TransactionId:  response.TxID,
+	}
+	balanceAsBytes, _ := json.Marshal(balanceData)
+	err = ctx.GetStub().SetEvent(BALANCE_EVENT, balanceAsBytes)
+	if err != nil {
+		response.Message = fmt.Sprintf("Error while Sending the Balance event: %s", err.Error())
+		logger.Error(response.Message)
+		return response, generateError(500, "BAL001", response.Message)
+	}
 	response.Success = true
-	response.Data = poolDataList
-	response.Message = "Pool history data has been successfully fetched"
+	response.Data = len(poolDataList)
+	response.Message = "Pool history has been successfully migrated"
 	return response, nil
 }
 
+// addPoolHistory store destroyed pool in pool history ordered by creation time
+func addPoolHistory(ctx contractapi.TransactionContextInterface, pool Pool) error {
+	poolKey, err := ctx.GetStub().CreateCompositeKey(poolHistoryPrefix, []string{fmt.Sprintf("%020d", pool.CreatedAt.Unix()), pool.PoolID})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", poolHistoryPrefix, err)
+	}
+	poolAsBytes, _ := json.Marshal(pool)
+	return ctx.GetStub().PutState(poolKey, poolAsBytes)
+}
+
 // Pool Config to retrieve the configuration date for the Pool
 func (bv *BusyVoting) PoolConfig(ctx contractapi.TransactionContextInterface) (*Response, error) {
 	response := &Response{
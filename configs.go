@@ -6,3 +6,26 @@ type TokenIssueFee struct {
 	NFT    string `json:"nft"`
 	GAME   string `json:"game"`
 }
+
+// FeeSchedule transaction fee of every operation type
+type FeeSchedule struct {
+	// Fees maps operation type to fee in BUSY wei
+	Fees map[string]string `json:"fees"`
+}
+
+// FeeRoutingConfig destination of collected fees, burn, treasury or stakers
+type FeeRoutingConfig struct {
+	// Default destination of operation types without their own route
+	Default string `json:"default"`
+	// Routes maps operation type to destination
+	Routes map[string]string `json:"routes"`
+	// Treasury wallet receiving fees routed to treasury
+	Treasury string `json:"treasury"`
+}
+
+// BatchTransferConfig configuration of batch transfers
+type BatchTransferConfig struct {
+	// FeeMode batch charges transfer fee once per batch and leg charges it for every recipient
+	FeeMode       string `json:"feeMode"`
+	MaxRecipients uint64 `json:"maxRecipients"`
+}
@@ -10,3 +10,85 @@ type BalanceEvent struct {
 	TransactionFee string        `json:"transactionFee"`
 	TransactionId  string        `json:"transactionId"`
 }
+
+type AllowanceEvent struct {
+	Owner          string        `json:"owner"`
+	Spender        string        `json:"spender"`
+	Token          string        `json:"token"`
+	Allowance      string        `json:"allowance"`
+	UserAddresses  []UserAddress `json:"userAddresses"`
+	TransactionFee string        `json:"transactionFee"`
+	TransactionId  string        `json:"transactionId"`
+}
+
+type FreezeEvent struct {
+	Address       string `json:"address"`
+	Frozen        bool   `json:"frozen"`
+	Reason        string `json:"reason"`
+	TransactionId string `json:"transactionId"`
+}
+
+type OwnershipEvent struct {
+	Symbol        string `json:"symbol"`
+	PreviousOwner string `json:"previousOwner"`
+	NewOwner      string `json:"newOwner"`
+	PendingOwner  string `json:"pendingOwner"`
+	TransactionId string `json:"transactionId"`
+}
+
+type PauseEvent struct {
+	Symbol        string `json:"symbol"`
+	Paused        bool   `json:"paused"`
+	TransactionId string `json:"transactionId"`
+}
+
+type HTLCEvent struct {
+	HTLC
+	UserAddresses  []UserAddress `json:"userAddresses"`
+	TransactionFee string        `json:"transactionFee"`
+	TransactionId  string        `json:"transactionId"`
+}
+
+type SwapEvent struct {
+	SwapOffer
+	UserAddresses  []UserAddress  `json:"userAddresses"`
+	NFTList        []NFTEventInfo `json:"nftEventInfo"`
+	TransactionFee string         `json:"transactionFee"`
+	TransactionId  string         `json:"transactionId"`
+}
+
+type ExchangeEvent struct {
+	Order
+	Trades         []Trade       `json:"trades"`
+	UserAddresses  []UserAddress `json:"userAddresses"`
+	TransactionFee string        `json:"transactionFee"`
+	TransactionId  string        `json:"transactionId"`
+}
+
+type PoolEvent struct {
+	LiquidityPool
+	Action         string        `json:"action"`
+	Account        string        `json:"account,omitempty"`
+	BaseAmount     string        `json:"baseAmount"`
+	QuoteAmount    string        `json:"quoteAmount"`
+	Shares         string        `json:"shares"`
+	UserAddresses  []UserAddress `json:"userAddresses"`
+	TransactionFee string        `json:"transactionFee"`
+	TransactionId  string        `json:"transactionId"`
+}
+
+type MultisigEvent struct {
+	Wallet         string            `json:"wallet"`
+	Proposal       *MultisigProposal `json:"proposal,omitempty"`
+	UserAddresses  []UserAddress     `json:"userAddresses"`
+	NFTList        []NFTEventInfo    `json:"nftEventInfo"`
+	TransactionFee string            `json:"transactionFee"`
+	TransactionId  string            `json:"transactionId"`
+}
+
+type StreamEvent struct {
+	Stream
+	UserAddresses  []UserAddress `json:"userAddresses"`
+	TransactionFee string        `json:"transactionFee"`
+	TransactionId  string        `json:"transactionId"`
+}
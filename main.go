@@ -13,25 +13,45 @@ var bigZero *big.Int = new(big.Int).SetUint64(0)
 func main() {
 	busy := new(Busy)
 	busy.UnknownTransaction = UnknownTransactionHandler
+	busy.TransactionContextHandler = new(BusyTransactionContext)
 	busy.Name = "Busy"
 
 	busyMessenger := new(BusyMessenger)
 	busyMessenger.UnknownTransaction = UnknownTransactionHandler
+	busyMessenger.TransactionContextHandler = new(BusyTransactionContext)
 	busyMessenger.Name = "BusyMessenger"
 
 	busyVoting := new(BusyVoting)
 	busyVoting.UnknownTransaction = UnknownTransactionHandler
+	busyVoting.TransactionContextHandler = new(BusyTransactionContext)
 	busyVoting.Name = "BusyVoting"
 
 	busyTokens := new(BusyTokens)
 	busyTokens.UnknownTransaction = UnknownTransactionHandler
+	busyTokens.TransactionContextHandler = new(BusyTransactionContext)
 	busyTokens.Name = "BusyTokens"
 
 	busyNFT := new(BusyNFT)
 	busyNFT.UnknownTransaction = UnknownTransactionHandler
+	busyNFT.TransactionContextHandler = new(BusyTransactionContext)
 	busyNFT.Name = "BusyNFT"
 
-	cc, err := contractapi.NewChaincode(busy, busyMessenger, busyVoting, busyTokens, busyNFT)
+	busySponsorship := new(BusySponsorship)
+	busySponsorship.UnknownTransaction = UnknownTransactionHandler
+	busySponsorship.TransactionContextHandler = new(BusyTransactionContext)
+	busySponsorship.Name = "BusySponsorship"
+
+	busyExchange := new(BusyExchange)
+	busyExchange.UnknownTransaction = UnknownTransactionHandler
+	busyExchange.TransactionContextHandler = new(BusyTransactionContext)
+	busyExchange.Name = "BusyExchange"
+
+	busyAMM := new(BusyAMM)
+	busyAMM.UnknownTransaction = UnknownTransactionHandler
+	busyAMM.TransactionContextHandler = new(BusyTransactionContext)
+	busyAMM.Name = "BusyAMM"
+
+	cc, err := contractapi.NewChaincode(busy, busyMessenger, busyVoting, busyTokens, busyNFT, busySponsorship, busyExchange, busyAMM)
 	cc.DefaultContract = busy.GetName()
 	if err != nil {
 		panic(err.Error())
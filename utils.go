@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
@@ -21,6 +22,68 @@ const (
 	REWARD_DENOMINATOR    = "100000000000000000000"
 	BUSY_COIN_SYMBOL      = "BUSY"
 	TOTAL_SUPPLY_KEY      = "TOTAL_SUPPLY"
+
+	UTXO_COMPACTION_THRESHOLD_KEY     = "utxoCompactionThreshold"
+	DEFAULT_UTXO_COMPACTION_THRESHOLD = 50
+	UTXO_MIGRATION_COMPLETE_KEY       = "utxoMigrationComplete"
+	MAX_TOKEN_DECIMALS                = 18
+)
+
+const (
+	SUPPLY_CATEGORY_WALLET          = "wallet"
+	SUPPLY_CATEGORY_STAKING         = "staking"
+	SUPPLY_CATEGORY_STAKER_FEE_POOL = "stakerFeePool"
+	SUPPLY_CATEGORY_HTLC            = "htlc"
+	SUPPLY_CATEGORY_SWAP            = "swap"
+	SUPPLY_CATEGORY_EXCHANGE        = "exchange"
+	SUPPLY_CATEGORY_LIQUIDITY_POOL  = "liquidityPool"
+	SUPPLY_CATEGORY_STREAM          = "stream"
+	SUPPLY_CATEGORY_VESTING         = "vesting"
+)
+
+// utxoPrefix composite key prefix of utxos, tag describes the utxo and a write sequence number
+// appended after it keeps utxos of the same address, token and tx apart
+const utxoPrefix = "address~token~txid~tag"
+
+// historyPrefix composite key prefix of transaction history, timestamp is zero padded to keep records in order
+// and a write sequence number appended after the tag keeps records of the same tx apart
+const historyPrefix = "address~token~timestamp~txid~tag"
+
+// freezePrefix composite key prefix of wallet freeze status
+const freezePrefix = "freeze~address"
+
+// vestingPrefix composite key prefix of vesting schedules, wallet can have any number of them
+const vestingPrefix = "vesting~address~scheduleId"
+
+// LEGACY_VESTING_SCHEDULE_ID id of the single schedule of wallet stored under vesting~<address>
+const LEGACY_VESTING_SCHEDULE_ID = "legacy"
+
+// feeAccountingPrefix composite key prefix of routed fees, period is the UTC day fee was collected and
+// a write sequence number appended after txid keeps fees routed by the same tx apart
+const feeAccountingPrefix = "period~destination~txType~txid"
+
+const (
+	FEE_ROUTING_CONFIG       = "FeeRoutingConfig"
+	FEE_DESTINATION_BURN     = "burn"
+	FEE_DESTINATION_TREASURY = "treasury"
+	FEE_DESTINATION_STAKERS  = "stakers"
+	FEE_PERIOD_LAYOUT        = "2006-01-02"
+	STAKER_FEE_POOL_KEY      = "STAKER_FEE_POOL"
+	MAX_FEE_PERIOD_DAYS      = 366
+)
+
+const (
+	STAKER_FEE_DISTRIBUTION_KEY = "StakerFeeDistribution"
+	DISTRIBUTION_PASS_COUNT     = "count"
+	DISTRIBUTION_PASS_PAY       = "pay"
+	// staking addresses are stored under plain keys staking-<txid>, '.' is the character after '-'
+	STAKING_ADDRESS_START_KEY = "staking-"
+	STAKING_ADDRESS_END_KEY   = "staking."
+)
+
+const (
+	HISTORY_DIRECTION_IN  = "in"
+	HISTORY_DIRECTION_OUT = "out"
 )
 
 // UnknownTransactionHandler returns a shim error with details of a bad transaction request
@@ -39,11 +102,40 @@ func getCommonName(ctx contractapi.TransactionContextInterface) (string, error)
 
 func pruneUTXOs(ctx contractapi.TransactionContextInterface, sender string, token string) (*big.Int, []string, error) {
 	// Query all the records where owner is sender and token is specified token
+	balance := new(big.Int).Set(bigZero)
+	var utxoKeys []string
+
+	utxoIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(utxoPrefix, []string{sender, token})
+	if err != nil {
+		return balance, nil, fmt.Errorf("failed to get state for prefix %v: %v", utxoPrefix, err)
+	}
+	defer utxoIterator.Close()
+
+	// Loop through all the fetched records and sum all of their amount
+	for utxoIterator.HasNext() {
+		queryResponse, err := utxoIterator.Next()
+		if err != nil {
+			return balance, nil, fmt.Errorf("failed to get the next state for prefix %v: %v", utxoPrefix, err)
+		}
+		var utxo UTXO
+		_ = json.Unmarshal(queryResponse.Value, &utxo)
+		utxoKeys = append(utxoKeys, queryResponse.Key)
+		bigAmount, _ := new(big.Int).SetString(utxo.Amount, 10)
+		balance = balance.Add(balance, bigAmount)
+	}
+
+	migrated, err := isUTXOMigrationComplete(ctx)
+	if err != nil {
+		return balance, nil, err
+	}
+	if migrated {
+		return balance, utxoKeys, nil
+	}
 
-	var utxo UTXO
-	balance, _ := new(big.Int).SetString("0", 10)
+	// utxos which are not migrated yet are still stored under plain keys
 	var queryString string = fmt.Sprintf(`{
 		"selector": {
+		   "docType": "utxo",
 		   "address": "%s",
 		   "token": "%s"
 		}
@@ -54,138 +146,434 @@ func pruneUTXOs(ctx contractapi.TransactionContextInterface, sender string, toke
 	}
 	defer resultIterator.Close()
 
-	// Loop through all the fetched records and sum all of their amount delete all existing utxo records
-	var utxoKeys []string
 	for resultIterator.HasNext() {
-		data, _ := resultIterator.Next()
-		_ = json.Unmarshal(data.Value, &utxo)
-		utxoKeys = append(utxoKeys, data.Key)
+		data, err := resultIterator.Next()
 		if err != nil {
 			return balance, nil, err
 		}
+		if isCompositeKey(data.Key) {
+			continue
+		}
+		var utxo UTXO
+		_ = json.Unmarshal(data.Value, &utxo)
+		utxoKeys = append(utxoKeys, data.Key)
 		bigAmount, _ := new(big.Int).SetString(utxo.Amount, 10)
 		balance = balance.Add(balance, bigAmount)
 	}
 	return balance, utxoKeys, nil
 }
 
-func transferHelper(ctx contractapi.TransactionContextInterface, sender string, recipiant string, amount *big.Int, token string, fee *big.Int) error {
-	logger.Infof("In transfer helper \n sender %s \n recipiant %s \n amount %s \n tokne %s \n fee %s \n ", sender, recipiant, amount.String(), token, fee.String())
-	var txID string = ctx.GetStub().GetTxID()
-
-	if amount.String() == "0" {
-		return nil
+// getUTXOBalancesOfToken balance of every address holding utxos of token, total supply key included
+func getUTXOBalancesOfToken(ctx contractapi.TransactionContextInterface, token string) (map[string]*big.Int, error) {
+	balances := map[string]*big.Int{}
+	addUTXOAmount := func(utxoAsBytes []byte) {
+		var utxo UTXO
+		_ = json.Unmarshal(utxoAsBytes, &utxo)
+		if utxo.Token != token {
+			return
+		}
+		bigAmount, _ := new(big.Int).SetString(utxo.Amount, 10)
+		if _, ok := balances[utxo.Address]; !ok {
+			balances[utxo.Address] = new(big.Int).Set(bigZero)
+		}
+		balances[utxo.Address].Add(balances[utxo.Address], bigAmount)
 	}
 
-	if token == BUSY_COIN_SYMBOL {
-		// Prune exsting utxo of sender and count his balance
-		balance, utxoKeys, err := pruneUTXOs(ctx, sender, token)
+	utxoIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(utxoPrefix, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state for prefix %v: %v", utxoPrefix, err)
+	}
+	defer utxoIterator.Close()
+	for utxoIterator.HasNext() {
+		queryResponse, err := utxoIterator.Next()
 		if err != nil {
-			return fmt.Errorf("error while pruning UTXOs: %s", err.Error())
+			return nil, fmt.Errorf("failed to get the next state for prefix %v: %v", utxoPrefix, err)
 		}
-		logger.Infof("balance of sender after prune utxo %s", balance.String())
+		addUTXOAmount(queryResponse.Value)
+	}
 
-		bigAmountWithTransferFee := new(big.Int).Set(fee).Add(fee, amount)
-		logger.Infof("bigAmountWithTransferFee: %s", bigAmountWithTransferFee)
+	migrated, err := isUTXOMigrationComplete(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if migrated {
+		return balances, nil
+	}
 
-		// Check if sender has enough balance
-		if bigAmountWithTransferFee.Cmp(balance) == 1 {
-			return fmt.Errorf("amount %s higher then your total balance %s", amount.String(), balance.String())
+	// utxos which are not migrated yet are still stored under plain keys
+	var queryString string = fmt.Sprintf(`{
+		"selector": {
+		   "docType": "utxo",
+		   "token": "%s"
+		}
+	}`, token)
+	resultIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	if err != nil {
+		return nil, err
+	}
+	defer resultIterator.Close()
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		if isCompositeKey(data.Key) {
+			continue
 		}
+		addUTXOAmount(data.Value)
+	}
+	return balances, nil
+}
 
-		// Delete existing utxos
-		for _, v := range utxoKeys {
-			_ = ctx.GetStub().DelState(v)
+// getLockedVestingAmount BUSY which is still locked in vestings, it is minted to the wallet only when unlocked.
+// Funded vestings are left out as their escrow addresses hold the locked tokens
+func getLockedVestingAmount(ctx contractapi.TransactionContextInterface) (*big.Int, error) {
+	locked := new(big.Int).Set(bigZero)
+	var queryString string = `{
+		"selector": {
+		   "docType": "lockedToken"
 		}
-		// Deduct balance of sender
-		balance = balance.Sub(balance, bigAmountWithTransferFee)
-		utxo := UTXO{
-			DocType: "utxo",
-			Address: sender,
-			Amount:  balance.String(),
-			Token:   token,
-		}
-		utxoAsBytes, _ := json.Marshal(utxo)
-		_ = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", txID, sender, recipiant, token), utxoAsBytes)
-
-		// Create new utxo for recipiant
-		utxo = UTXO{
-			DocType: "utxo",
-			Address: recipiant,
-			Amount:  amount.String(),
-			Token:   token,
-		}
-		utxoAsBytes, _ = json.Marshal(utxo)
-		err = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", txID, recipiant, sender, token), utxoAsBytes)
+	}`
+	resultIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	if err != nil {
+		return nil, err
+	}
+	defer resultIterator.Close()
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
 		if err != nil {
-			return fmt.Errorf("error while put state in ledger: %s", err.Error())
+			return nil, err
 		}
-		return nil
-	} else {
-		// Prune exsting utxo of sender and count his balance
-		tokenBalance, tokenUtxoKeys, err := pruneUTXOs(ctx, sender, token)
+		var lockedToken LockedTokens
+		_ = json.Unmarshal(data.Value, &lockedToken)
+		if lockedToken.Escrow != "" {
+			continue
+		}
+		totalAmount, _ := new(big.Int).SetString(lockedToken.TotalAmount, 10)
+		releasedAmount, _ := new(big.Int).SetString(lockedToken.ReleasedAmount, 10)
+		if totalAmount == nil || releasedAmount == nil {
+			continue
+		}
+		locked.Add(locked, totalAmount.Sub(totalAmount, releasedAmount))
+		if revokedAmount, ok := new(big.Int).SetString(lockedToken.RevokedAmount, 10); ok {
+			locked.Sub(locked, revokedAmount)
+		}
+	}
+	return locked, nil
+}
+
+// getVestingSchedules all vesting schedules of address, including the single schedule stored before
+// wallets could have more of them
+func getVestingSchedules(ctx contractapi.TransactionContextInterface, address string) ([]*LockedTokens, error) {
+	schedules := []*LockedTokens{}
+	legacySchedule, err := getVestingSchedule(ctx, address, LEGACY_VESTING_SCHEDULE_ID)
+	if err != nil {
+		return nil, err
+	}
+	if legacySchedule != nil {
+		schedules = append(schedules, legacySchedule)
+	}
+
+	resultIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(vestingPrefix, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state for prefix %v: %v", vestingPrefix, err)
+	}
+	defer resultIterator.Close()
+	for resultIterator.HasNext() {
+		data, err := resultIterator.Next()
 		if err != nil {
-			return fmt.Errorf("error while pruning token UTXOs: %s", err.Error())
+			return nil, fmt.Errorf("failed to get the next state for prefix %v: %v", vestingPrefix, err)
+		}
+		schedule := &LockedTokens{}
+		if err := json.Unmarshal(data.Value, schedule); err != nil {
+			return nil, fmt.Errorf("error while retrieving vesting schedule: %s", err.Error())
 		}
-		busyBalance, busyUtxoKeys, err := pruneUTXOs(ctx, sender, BUSY_COIN_SYMBOL)
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// getVestingSchedule vesting schedule of address, nil when it does not exist
+func getVestingSchedule(ctx contractapi.TransactionContextInterface, address string, scheduleId string) (*LockedTokens, error) {
+	scheduleKey, err := getVestingScheduleKey(ctx, address, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+	scheduleAsBytes, err := ctx.GetStub().GetState(scheduleKey)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching vesting schedule: %s", err.Error())
+	}
+	if scheduleAsBytes == nil {
+		return nil, nil
+	}
+	schedule := &LockedTokens{}
+	if err := json.Unmarshal(scheduleAsBytes, schedule); err != nil {
+		return nil, fmt.Errorf("error while retrieving vesting schedule: %s", err.Error())
+	}
+	schedule.ID = scheduleId
+	schedule.Address = address
+	return schedule, nil
+}
+
+func putVestingSchedule(ctx contractapi.TransactionContextInterface, schedule *LockedTokens) error {
+	scheduleKey, err := getVestingScheduleKey(ctx, schedule.Address, schedule.ID)
+	if err != nil {
+		return err
+	}
+	scheduleAsBytes, _ := json.Marshal(schedule)
+	return ctx.GetStub().PutState(scheduleKey, scheduleAsBytes)
+}
+
+// getVestingScheduleKey legacy schedule keeps its original key so that existing vestings stay where they are
+func getVestingScheduleKey(ctx contractapi.TransactionContextInterface, address string, scheduleId string) (string, error) {
+	if scheduleId == LEGACY_VESTING_SCHEDULE_ID {
+		return fmt.Sprintf("vesting~%s", address), nil
+	}
+	scheduleKey, err := ctx.GetStub().CreateCompositeKey(vestingPrefix, []string{address, scheduleId})
+	if err != nil {
+		return "", fmt.Errorf("failed to create the composite key for prefix %s: %v", vestingPrefix, err)
+	}
+	return scheduleKey, nil
+}
+
+// getVestingToken token of vesting schedule, schedules created before BUSY20 vesting existed are BUSY
+func getVestingToken(schedule *LockedTokens) string {
+	if schedule.Token == "" {
+		return BUSY_COIN_SYMBOL
+	}
+	return schedule.Token
+}
+
+// getVestingEscrowAddress every funded vesting has its own escrow address so that releasing one never touches funds of another
+func getVestingEscrowAddress(id string) string {
+	return "vesting-" + id
+}
+
+// getVestedAmount part of schedule vested at now, released linearly between start and release.
+// Nothing vests before the cliff and a revoked schedule stops at what was vested when it was revoked
+func getVestedAmount(schedule *LockedTokens, now uint64) *big.Int {
+	totalAmount, _ := new(big.Int).SetString(schedule.TotalAmount, 10)
+	if schedule.Revoked {
+		revokedAmount, _ := new(big.Int).SetString(schedule.RevokedAmount, 10)
+		return totalAmount.Sub(totalAmount, revokedAmount)
+	}
+	if now < schedule.StartedAt || now < schedule.CliffAt {
+		return new(big.Int).Set(bigZero)
+	}
+	if now >= schedule.ReleaseAt {
+		return totalAmount
+	}
+	elapsed := new(big.Int).SetUint64(now - schedule.StartedAt)
+	duration := new(big.Int).SetUint64(schedule.ReleaseAt - schedule.StartedAt)
+	return totalAmount.Mul(elapsed, totalAmount).Div(totalAmount, duration)
+}
+
+// getHolderCategory category of balance holder used in supply audit
+func getHolderCategory(address string) string {
+	switch {
+	case address == STAKER_FEE_POOL_KEY:
+		return SUPPLY_CATEGORY_STAKER_FEE_POOL
+	case strings.HasPrefix(address, "staking-"):
+		return SUPPLY_CATEGORY_STAKING
+	case strings.HasPrefix(address, "htlc-"):
+		return SUPPLY_CATEGORY_HTLC
+	case strings.HasPrefix(address, "swap-"):
+		return SUPPLY_CATEGORY_SWAP
+	case strings.HasPrefix(address, "order-"):
+		return SUPPLY_CATEGORY_EXCHANGE
+	case strings.HasPrefix(address, "pool-"):
+		return SUPPLY_CATEGORY_LIQUIDITY_POOL
+	case strings.HasPrefix(address, "stream-"):
+		return SUPPLY_CATEGORY_STREAM
+	case strings.HasPrefix(address, "vesting-"):
+		return SUPPLY_CATEGORY_VESTING
+	default:
+		return SUPPLY_CATEGORY_WALLET
+	}
+}
+
+// BusyTransactionContext transaction context of every contract, it numbers the records written in a transaction
+type BusyTransactionContext struct {
+	contractapi.TransactionContext
+	writeSeq uint64
+}
+
+// getWriteSeq zero padded sequence number of next record written in the transaction
+func getWriteSeq(ctx contractapi.TransactionContextInterface) (string, error) {
+	busyCtx, ok := ctx.(*BusyTransactionContext)
+	if !ok {
+		return "", errors.New("transaction context does not number written records")
+	}
+	busyCtx.writeSeq++
+	return fmt.Sprintf("%06d", busyCtx.writeSeq), nil
+}
+
+// putUTXO store utxo of address under the utxo composite key
+func putUTXO(ctx contractapi.TransactionContextInterface, address string, amount *big.Int, token string, tag string) error {
+	seq, err := getWriteSeq(ctx)
+	if err != nil {
+		return err
+	}
+	utxoKey, err := ctx.GetStub().CreateCompositeKey(utxoPrefix, []string{address, token, ctx.GetStub().GetTxID(), tag, seq})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", utxoPrefix, err)
+	}
+	utxo := UTXO{
+		DocType: "utxo",
+		Address: address,
+		Amount:  amount.String(),
+		Token:   token,
+	}
+	utxoAsBytes, _ := json.Marshal(utxo)
+	return ctx.GetStub().PutState(utxoKey, utxoAsBytes)
+}
+
+// isCompositeKey composite keys always start with the null namespace character
+func isCompositeKey(key string) bool {
+	return strings.HasPrefix(key, "\x00")
+}
+
+func isUTXOMigrationComplete(ctx contractapi.TransactionContextInterface) (bool, error) {
+	migratedAsBytes, err := ctx.GetStub().GetState(UTXO_MIGRATION_COMPLETE_KEY)
+	if err != nil {
+		return false, fmt.Errorf("error while fetching utxo migration status: %s", err.Error())
+	}
+	return string(migratedAsBytes) == "true", nil
+}
+
+func transferHelper(ctx contractapi.TransactionContextInterface, sender string, recipiant string, amount *big.Int, token string, fee *big.Int, txType string) error {
+	return multiTransferHelper(ctx, sender, []string{recipiant}, []*big.Int{amount}, token, fee, txType)
+}
+
+// multiTransferHelper transfer amounts to recipients with a single prune of sender utxos, fee is always deducted in BUSY.
+// Sender must not be debited again for the same token in the same transaction as the prune does not see those writes.
+// Fee of txType is charged from sponsor of sender when a sponsorship applies, routing the fee is left to the caller.
+func multiTransferHelper(ctx contractapi.TransactionContextInterface, sender string, recipiants []string, amounts []*big.Int, token string, fee *big.Int, txType string) error {
+	logger.Infof("In transfer helper \n sender %s \n recipiants %v \n amounts %v \n tokne %s \n fee %s \n ", sender, recipiants, amounts, token, fee.String())
+	if len(recipiants) != len(amounts) {
+		return fmt.Errorf("number of recipiants %d does not match number of amounts %d", len(recipiants), len(amounts))
+	}
+
+	// Sum amount of every recipiant, order of first appearance keeps writes deterministic
+	totalAmount := new(big.Int).Set(bigZero)
+	recipiantAmounts := map[string]*big.Int{}
+	var recipiantOrder []string
+	for i, recipiant := range recipiants {
+		if amounts[i].Cmp(bigZero) == 0 {
+			continue
+		}
+		if _, ok := recipiantAmounts[recipiant]; !ok {
+			recipiantAmounts[recipiant] = new(big.Int).Set(bigZero)
+			recipiantOrder = append(recipiantOrder, recipiant)
+		}
+		recipiantAmounts[recipiant].Add(recipiantAmounts[recipiant], amounts[i])
+		totalAmount.Add(totalAmount, amounts[i])
+	}
+	if totalAmount.Cmp(bigZero) == 0 {
+		return nil
+	}
+	changeTag := "to~" + recipiantOrder[0]
+	if len(recipiantOrder) > 1 {
+		changeTag = "batch"
+	}
+
+	err := checkWalletNotFrozen(ctx, append([]string{sender}, recipiantOrder...)...)
+	if err != nil {
+		return err
+	}
+	err = checkTokenNotPaused(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	sponsorship, err := findSponsorship(ctx, sender, fee, txType, []string{token})
+	if err != nil {
+		return err
+	}
+	sponsoredFee := bigZero
+	if sponsorship != nil {
+		sponsoredFee = fee
+		fee = bigZero
+	}
+
+	// Prune exsting utxo of sender and count his balance
+	tokenBalance, tokenUtxoKeys, err := pruneUTXOs(ctx, sender, token)
+	if err != nil {
+		return fmt.Errorf("error while pruning UTXOs: %s", err.Error())
+	}
+	logger.Infof("balance of sender after prune utxo %s", tokenBalance.String())
+	if token == BUSY_COIN_SYMBOL {
+		totalAmount = totalAmount.Add(totalAmount, fee)
+		logger.Infof("bigAmountWithTransferFee: %s", totalAmount)
+	}
+	// Check if sender has enough balance
+	if totalAmount.Cmp(tokenBalance) == 1 {
+		return fmt.Errorf("amount %s higher then your total balance %s", totalAmount.String(), tokenBalance.String())
+	}
+
+	var busyBalance *big.Int
+	var busyUtxoKeys []string
+	if token != BUSY_COIN_SYMBOL && fee.Cmp(bigZero) == 1 {
+		busyBalance, busyUtxoKeys, err = pruneUTXOs(ctx, sender, BUSY_COIN_SYMBOL)
 		if err != nil {
 			return fmt.Errorf("error while pruning busy UTXOs: %s", err.Error())
 		}
-
-		// Check if sender has enough balance
 		if fee.Cmp(busyBalance) == 1 {
 			return fmt.Errorf("amount %s higher then your total balance %s", fee.String(), busyBalance.String())
 		}
-		if amount.Cmp(tokenBalance) == 1 {
-			return fmt.Errorf("amount %s higher then your total balance %s", amount.String(), tokenBalance.String())
+	}
+
+	// Delete existing utxos
+	for _, v := range tokenUtxoKeys {
+		_ = ctx.GetStub().DelState(v)
+	}
+	// Deduct balance of sender
+	tokenBalance = tokenBalance.Sub(tokenBalance, totalAmount)
+	err = putUTXO(ctx, sender, tokenBalance, token, changeTag)
+	if err != nil {
+		return fmt.Errorf("error while put state in ledger: %s", err.Error())
+	}
+
+	// Create new utxo for every recipiant
+	for i, recipiant := range recipiantOrder {
+		err = putUTXO(ctx, recipiant, recipiantAmounts[recipiant], token, "from~"+sender)
+		if err != nil {
+			return fmt.Errorf("error while put state in ledger: %s", err.Error())
 		}
 
-		// Delete existing utxos
-		for _, v := range tokenUtxoKeys {
-			_ = ctx.GetStub().DelState(v)
+		legFee := bigZero
+		if i == 0 && token == BUSY_COIN_SYMBOL {
+			legFee = fee
 		}
-		for _, v := range busyUtxoKeys {
-			_ = ctx.GetStub().DelState(v)
+		err = addHistory(ctx, sender, token, HISTORY_DIRECTION_OUT, recipiant, recipiantAmounts[recipiant], legFee, "to~"+recipiant)
+		if err != nil {
+			return err
 		}
-		// Deduct balance of sender
-		tokenBalance = tokenBalance.Sub(tokenBalance, amount)
-		utxo := UTXO{
-			DocType: "utxo",
-			Address: sender,
-			Amount:  tokenBalance.String(),
-			Token:   token,
-		}
-		utxoAsBytes, _ := json.Marshal(utxo)
-		_ = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", txID, sender, recipiant, token), utxoAsBytes)
-
-		// Create new utxo for recipiant
-		utxo = UTXO{
-			DocType: "utxo",
-			Address: recipiant,
-			Amount:  amount.String(),
-			Token:   token,
-		}
-		utxoAsBytes, _ = json.Marshal(utxo)
-		err = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", txID, recipiant, sender, token), utxoAsBytes)
+		err = addHistory(ctx, recipiant, token, HISTORY_DIRECTION_IN, sender, recipiantAmounts[recipiant], bigZero, "from~"+sender)
 		if err != nil {
-			return fmt.Errorf("error while put state in ledger: %s", err.Error())
+			return err
 		}
+	}
 
-		busyBalance = busyBalance.Sub(busyBalance, fee)
+	if busyBalance != nil {
+		for _, v := range busyUtxoKeys {
+			_ = ctx.GetStub().DelState(v)
+		}
 		// deduct tx fee from sender
-		utxo = UTXO{
-			DocType: "utxo",
-			Address: sender,
-			Amount:  busyBalance.String(),
-			Token:   BUSY_COIN_SYMBOL,
-		}
-		utxoAsBytes, _ = json.Marshal(utxo)
-		err = ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", txID, recipiant, sender, BUSY_COIN_SYMBOL), utxoAsBytes)
+		busyBalance = busyBalance.Sub(busyBalance, fee)
+		err = putUTXO(ctx, sender, busyBalance, BUSY_COIN_SYMBOL, changeTag)
 		if err != nil {
 			return fmt.Errorf("error while put state in ledger: %s", err.Error())
 		}
-		return nil
+		err = addHistory(ctx, sender, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_OUT, "", bigZero, fee, "fee")
+		if err != nil {
+			return err
+		}
+	}
+	if sponsorship != nil {
+		return chargeSponsor(ctx, sponsorship, sender, sponsoredFee, txType)
 	}
+	return nil
 }
 
 func getBalanceHelper(ctx contractapi.TransactionContextInterface, address string, token string) (*big.Int, error) {
@@ -217,27 +605,188 @@ func getDefaultWalletAddress(ctx contractapi.TransactionContextInterface, common
 	return user.DefaultWallet, nil
 }
 
-func addUTXO(ctx contractapi.TransactionContextInterface, address string, amount *big.Int, symbol string) error {
-	utxo := UTXO{
-		DocType: "utxo",
+// getFreezeInfo freeze status of wallet address, not frozen if admin never froze it
+func getFreezeInfo(ctx contractapi.TransactionContextInterface, address string) (*FreezeInfo, error) {
+	freezeKey, err := ctx.GetStub().CreateCompositeKey(freezePrefix, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", freezePrefix, err)
+	}
+	freezeInfoAsBytes, err := ctx.GetStub().GetState(freezeKey)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching freeze status: %s", err.Error())
+	}
+	freezeInfo := FreezeInfo{
+		DocType: "freezeInfo",
 		Address: address,
-		Amount:  amount.String(),
-		Token:   symbol,
 	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err := ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s", ctx.GetStub().GetTxID(), address, symbol), utxoAsBytes)
-	return err
+	if freezeInfoAsBytes != nil {
+		_ = json.Unmarshal(freezeInfoAsBytes, &freezeInfo)
+	}
+	return &freezeInfo, nil
+}
+
+// checkWalletNotFrozen returns error if any of the addresses is frozen
+func checkWalletNotFrozen(ctx contractapi.TransactionContextInterface, addresses ...string) error {
+	for _, address := range addresses {
+		freezeInfo, err := getFreezeInfo(ctx, address)
+		if err != nil {
+			return err
+		}
+		if freezeInfo.Frozen {
+			return fmt.Errorf("wallet %s is frozen", address)
+		}
+	}
+	return nil
+}
+
+// resolveSenderWallet wallet of user to spend from, default wallet when fromWallet is empty
+func resolveSenderWallet(ctx contractapi.TransactionContextInterface, commonName string, fromWallet string) (string, error) {
+	if fromWallet == "" {
+		return getDefaultWalletAddress(ctx, commonName)
+	}
+	walletAsBytes, err := ctx.GetStub().GetState(fromWallet)
+	if err != nil {
+		return "", fmt.Errorf("error while fetching wallet: %s", err.Error())
+	}
+	if walletAsBytes == nil {
+		return "", fmt.Errorf("wallet %s does not exist", fromWallet)
+	}
+	var wallet Wallet
+	_ = json.Unmarshal(walletAsBytes, &wallet)
+	if wallet.DocType != "wallet" || wallet.UserID != commonName {
+		return "", fmt.Errorf("wallet %s does not belong to %s", fromWallet, commonName)
+	}
+	return wallet.Address, nil
+}
+
+func addUTXO(ctx contractapi.TransactionContextInterface, address string, amount *big.Int, symbol string) error {
+	err := putUTXO(ctx, address, amount, symbol, "add")
+	if err != nil {
+		return err
+	}
+	if amount.Sign() == -1 {
+		return addHistory(ctx, address, symbol, HISTORY_DIRECTION_OUT, "", new(big.Int).Neg(amount), bigZero, "add")
+	}
+	return addHistory(ctx, address, symbol, HISTORY_DIRECTION_IN, "", amount, bigZero, "add")
 }
 
 func addClaimUTXO(ctx contractapi.TransactionContextInterface, address string, stakingAddr string, amount *big.Int, symbol string) error {
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  amount.String(),
-		Token:   symbol,
+	err := putUTXO(ctx, address, amount, symbol, "claim~"+stakingAddr)
+	if err != nil {
+		return err
 	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err := ctx.GetStub().PutState(fmt.Sprintf("%s~%s~%s~%s", ctx.GetStub().GetTxID(), address, symbol, stakingAddr), utxoAsBytes)
+	return addHistory(ctx, address, symbol, HISTORY_DIRECTION_IN, stakingAddr, amount, bigZero, "claim~"+stakingAddr)
+}
+
+// addHistory write history record of address for token, records of total supply are not kept
+func addHistory(ctx contractapi.TransactionContextInterface, address string, token string, direction string, counterparty string, amount *big.Int, fee *big.Int, tag string) error {
+	if address == TOTAL_SUPPLY_KEY || address == TOTAL_SUPPLY_KEY_NFT || address == STAKER_FEE_POOL_KEY {
+		return nil
+	}
+	seq, err := getWriteSeq(ctx)
+	if err != nil {
+		return err
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	historyKey, err := ctx.GetStub().CreateCompositeKey(historyPrefix, []string{address, token, fmt.Sprintf("%020d", now.Seconds), ctx.GetStub().GetTxID(), tag, seq})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", historyPrefix, err)
+	}
+	record := TransactionRecord{
+		DocType:      "txHistory",
+		Address:      address,
+		Token:        token,
+		Direction:    direction,
+		Counterparty: counterparty,
+		Amount:       amount.String(),
+		Fee:          fee.String(),
+		Type:         getTxType(ctx),
+		TxID:         ctx.GetStub().GetTxID(),
+		Timestamp:    uint64(now.Seconds),
+	}
+	recordAsBytes, _ := json.Marshal(record)
+	err = ctx.GetStub().PutState(historyKey, recordAsBytes)
+	if err != nil {
+		return fmt.Errorf("error while writing history: %s", err.Error())
+	}
+	return nil
+}
+
+// getContractName name of invoked contract, functions without contract name belong to default Busy contract
+func getContractName(ctx contractapi.TransactionContextInterface) string {
+	fcn, _ := ctx.GetStub().GetFunctionAndParameters()
+	if i := strings.LastIndex(fcn, ":"); i >= 0 {
+		return fcn[:i]
+	}
+	return "Busy"
+}
+
+// getTxType name of invoked function without contract name
+func getTxType(ctx contractapi.TransactionContextInterface) string {
+	fcn, _ := ctx.GetStub().GetFunctionAndParameters()
+	if i := strings.LastIndex(fcn, ":"); i >= 0 {
+		return fcn[i+1:]
+	}
+	return fcn
+}
+
+// compactUTXOs merge all the utxos of address for token into a single utxo holding the same balance.
+// Nothing is done when address has less than minUTXOs utxos. It has to be called before anything
+// else in the transaction writes utxos of address for token as the query does not see those writes.
+func compactUTXOs(ctx contractapi.TransactionContextInterface, address string, token string, minUTXOs int) (*big.Int, int, error) {
+	if minUTXOs < 2 {
+		minUTXOs = 2
+	}
+	balance, utxoKeys, err := pruneUTXOs(ctx, address, token)
+	if err != nil {
+		return bigZero, 0, fmt.Errorf("error while pruning UTXOs: %s", err.Error())
+	}
+	if len(utxoKeys) < minUTXOs {
+		return balance, 0, nil
+	}
+
+	for _, v := range utxoKeys {
+		err = ctx.GetStub().DelState(v)
+		if err != nil {
+			return bigZero, 0, fmt.Errorf("error while deleting utxo %s: %s", v, err.Error())
+		}
+	}
+	err = putUTXO(ctx, address, balance, token, "compact")
+	if err != nil {
+		return bigZero, 0, fmt.Errorf("error while put state in ledger: %s", err.Error())
+	}
+	logger.Infof("compacted %d utxos of %s for token %s into balance %s", len(utxoKeys), address, token, balance.String())
+	return balance, len(utxoKeys), nil
+}
+
+// getUTXOCompactionThreshold get number of utxos after which balance of an address is compacted automatically, 0 means disabled
+func getUTXOCompactionThreshold(ctx contractapi.TransactionContextInterface) (int, error) {
+	thresholdAsBytes, err := ctx.GetStub().GetState(UTXO_COMPACTION_THRESHOLD_KEY)
+	if err != nil {
+		return 0, err
+	}
+	if thresholdAsBytes == nil {
+		return DEFAULT_UTXO_COMPACTION_THRESHOLD, nil
+	}
+	threshold, err := strconv.Atoi(string(thresholdAsBytes))
+	if err != nil {
+		return 0, fmt.Errorf("invalid utxo compaction threshold %s", string(thresholdAsBytes))
+	}
+	return threshold, nil
+}
+
+// autoCompactUTXOs compact utxos of address for token once they reach the configured threshold.
+// Call it before anything else in the transaction writes utxos of address for token and never for an
+// address that is pruned later in the same transaction, the prune would not see the compacted utxo
+func autoCompactUTXOs(ctx contractapi.TransactionContextInterface, address string, token string) error {
+	threshold, err := getUTXOCompactionThreshold(ctx)
+	if err != nil {
+		return err
+	}
+	if threshold == 0 {
+		return nil
+	}
+	_, _, err = compactUTXOs(ctx, address, token, threshold)
 	return err
 }
 
@@ -272,58 +821,185 @@ func addTotalSupplyUTXO(ctx contractapi.TransactionContextInterface, tokenSymbol
 	return nil
 }
 
-// burnTxFeeWithTotalSupply burn tx fee from user and reduce total supply accordingly
-func burnTxFeeWithTotalSupply(ctx contractapi.TransactionContextInterface, address string, token string) error {
-	txFee, err := getCurrentTxFee(ctx)
+// chargeTxFee charge fee of operation type from fee schedule to address
+func chargeTxFee(ctx contractapi.TransactionContextInterface, address string, txType string, tokens ...string) error {
+	txFee, err := getTxFee(ctx, txType)
 	if err != nil {
 		return err
 	}
-	minusOne, _ := new(big.Int).SetString("-1", 10)
 	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
-	err = addTotalSupplyUTXO(ctx, token, new(big.Int).Set(bigTxFee).Mul(minusOne, bigTxFee))
+	return chargeFee(ctx, address, bigTxFee, txType, tokens...)
+}
+
+// chargeFee deduct fee in BUSY from address, or from its sponsor when a sponsorship covers the operation
+// on tokens, and route it to the configured destination
+func chargeFee(ctx contractapi.TransactionContextInterface, address string, fee *big.Int, txType string, tokens ...string) error {
+	if fee.Cmp(bigZero) == 0 {
+		return nil
+	}
+	sponsorship, err := findSponsorship(ctx, address, fee, txType, tokens)
 	if err != nil {
 		return err
 	}
-
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, minusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
+	if sponsorship != nil {
+		err = chargeSponsor(ctx, sponsorship, address, fee, txType)
+	} else {
+		err = debitFee(ctx, address, fee, txType)
 	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err = ctx.GetStub().PutState(fmt.Sprintf("burnTxFee~%s~%s~%s", ctx.GetStub().GetTxID(), address, BUSY_COIN_SYMBOL), utxoAsBytes)
 	if err != nil {
 		return err
 	}
-	return nil
+	return routeFee(ctx, txType, fee)
 }
 
-// burnTxFee to burn tx fee from the user address
-func burnTxFee(ctx contractapi.TransactionContextInterface, address string, token string) error {
-	txFee, err := getCurrentTxFee(ctx)
+// debitFee deduct fee in BUSY from address without routing it
+func debitFee(ctx contractapi.TransactionContextInterface, address string, fee *big.Int, txType string) error {
+	err := putUTXO(ctx, address, new(big.Int).Mul(fee, minusOne), BUSY_COIN_SYMBOL, "fee~"+txType)
 	if err != nil {
 		return err
 	}
-	minusOne, _ := new(big.Int).SetString("-1", 10)
-	bigTxFee, _ := new(big.Int).SetString(txFee, 10)
+	return addHistory(ctx, address, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_OUT, "", bigZero, fee, "fee~"+txType)
+}
 
-	utxo := UTXO{
-		DocType: "utxo",
-		Address: address,
-		Amount:  bigTxFee.Mul(bigTxFee, minusOne).String(),
-		Token:   BUSY_COIN_SYMBOL,
+// getFeeRoutingConfig get fee destinations from blockchain, fees are burned when nothing is configured
+func getFeeRoutingConfig(ctx contractapi.TransactionContextInterface) (*FeeRoutingConfig, error) {
+	configAsBytes, err := ctx.GetStub().GetState(FEE_ROUTING_CONFIG)
+	if err != nil {
+		return nil, err
 	}
-	utxoAsBytes, _ := json.Marshal(utxo)
-	err = ctx.GetStub().PutState(fmt.Sprintf("burnTxFee~%s~%s~%s", ctx.GetStub().GetTxID(), address, BUSY_COIN_SYMBOL), utxoAsBytes)
+	config := &FeeRoutingConfig{
+		Default: FEE_DESTINATION_BURN,
+		Routes:  map[string]string{},
+	}
+	if configAsBytes == nil {
+		return config, nil
+	}
+	if err := json.Unmarshal(configAsBytes, config); err != nil {
+		return nil, err
+	}
+	if config.Routes == nil {
+		config.Routes = map[string]string{}
+	}
+	return config, nil
+}
+
+// routeFee send fee which already left the payer to its destination and record it for accounting.
+// Burned fees reduce total supply, treasury fees are credited to the treasury wallet and staker
+// fees are kept in the staker fee pool until DistributeStakerFees is called.
+func routeFee(ctx contractapi.TransactionContextInterface, txType string, fee *big.Int) error {
+	if fee.Cmp(bigZero) != 1 {
+		return nil
+	}
+	config, err := getFeeRoutingConfig(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
+	destination, ok := config.Routes[txType]
+	if !ok {
+		destination = config.Default
+	}
+
+	// tag differs from the one used to charge the fee so payer and destination can be the same address
+	tag := "collect~" + txType
+	switch destination {
+	case FEE_DESTINATION_TREASURY:
+		err = putUTXO(ctx, config.Treasury, fee, BUSY_COIN_SYMBOL, tag)
+		if err == nil {
+			err = addHistory(ctx, config.Treasury, BUSY_COIN_SYMBOL, HISTORY_DIRECTION_IN, "", fee, bigZero, tag)
+		}
+	case FEE_DESTINATION_STAKERS:
+		err = putUTXO(ctx, STAKER_FEE_POOL_KEY, fee, BUSY_COIN_SYMBOL, tag)
+	default:
+		destination = FEE_DESTINATION_BURN
+		err = putUTXO(ctx, TOTAL_SUPPLY_KEY, new(big.Int).Mul(fee, minusOne), BUSY_COIN_SYMBOL, tag)
+	}
+	if err != nil {
+		return err
+	}
+
+	seq, err := getWriteSeq(ctx)
+	if err != nil {
+		return err
+	}
+	now, _ := ctx.GetStub().GetTxTimestamp()
+	period := time.Unix(now.Seconds, 0).UTC().Format(FEE_PERIOD_LAYOUT)
+	accountingKey, err := ctx.GetStub().CreateCompositeKey(feeAccountingPrefix, []string{period, destination, txType, ctx.GetStub().GetTxID(), seq})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", feeAccountingPrefix, err)
+	}
+	return ctx.GetStub().PutState(accountingKey, []byte(fee.String()))
 }
 
-// getCurrentTxFee get current tx fee from blockchain
-func getCurrentTxFee(ctx contractapi.TransactionContextInterface) (string, error) {
+// getStakerFeeDistribution get staker fee distribution in progress, nil when there is none
+func getStakerFeeDistribution(ctx contractapi.TransactionContextInterface) (*StakerFeeDistribution, error) {
+	distributionAsBytes, err := ctx.GetStub().GetState(STAKER_FEE_DISTRIBUTION_KEY)
+	if err != nil {
+		return nil, err
+	}
+	if distributionAsBytes == nil {
+		return nil, nil
+	}
+	distribution := &StakerFeeDistribution{}
+	if err := json.Unmarshal(distributionAsBytes, distribution); err != nil {
+		return nil, err
+	}
+	return distribution, nil
+}
+
+// getFeeSchedule get fee schedule from blockchain, networks deployed before the fee schedule
+// existed keep the legacy transfer fee for transfers and TRANSFER_FEE for everything else
+func getFeeSchedule(ctx contractapi.TransactionContextInterface) (*FeeSchedule, error) {
+	feeScheduleAsBytes, err := ctx.GetStub().GetState(FEE_SCHEDULE_KEY)
+	if err != nil {
+		return nil, err
+	}
+	if feeScheduleAsBytes == nil {
+		transferFee := TRANSFER_FEE
+		transferFeesAsBytes, err := ctx.GetStub().GetState("transferFees")
+		if err != nil {
+			return nil, err
+		}
+		if transferFeesAsBytes != nil {
+			transferFee = string(transferFeesAsBytes)
+		}
+		return defaultFeeSchedule(transferFee), nil
+	}
+	feeSchedule := &FeeSchedule{}
+	if err := json.Unmarshal(feeScheduleAsBytes, feeSchedule); err != nil {
+		return nil, err
+	}
+	if feeSchedule.Fees == nil {
+		feeSchedule.Fees = map[string]string{}
+	}
+	return feeSchedule, nil
+}
+
+// defaultFeeSchedule fee schedule charging transferFee for transfers and TRANSFER_FEE for every other operation
+func defaultFeeSchedule(transferFee string) *FeeSchedule {
+	fees := map[string]string{
+		DEFAULT_FEE_TYPE: TRANSFER_FEE,
+	}
+	for _, txType := range FEE_TYPES {
+		fees[txType] = TRANSFER_FEE
+	}
+	// only transfers used to read the transferFees key
+	fees["transfer"] = transferFee
+	fees["batchTransfer"] = transferFee
+	return &FeeSchedule{Fees: fees}
+}
+
+// getTxFee get fee of operation from fee schedule, unknown operations pay the default fee
+func getTxFee(ctx contractapi.TransactionContextInterface, txType string) (string, error) {
+	feeSchedule, err := getFeeSchedule(ctx)
+	if err != nil {
+		return "", err
+	}
+	if fee, ok := feeSchedule.Fees[txType]; ok {
+		return fee, nil
+	}
+	if fee, ok := feeSchedule.Fees[DEFAULT_FEE_TYPE]; ok {
+		return fee, nil
+	}
 	return TRANSFER_FEE, nil
 }
 
@@ -395,6 +1071,162 @@ func ifTokenExists(ctx contractapi.TransactionContextInterface, tokenSymbol stri
 	return true, nil
 }
 
+func getBusy20Token(ctx contractapi.TransactionContextInterface, symbol string) (*Token, error) {
+	tokenAsBytes, err := ctx.GetStub().GetState(generateTokenStateAddress(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while fetching token details: %s", err.Error())
+	}
+	if tokenAsBytes == nil {
+		return nil, fmt.Errorf("symbol %s does not exist", symbol)
+	}
+	var token Token
+	if err := json.Unmarshal(tokenAsBytes, &token); err != nil {
+		return nil, fmt.Errorf("error while retrieving token details: %s", err.Error())
+	}
+	return &token, nil
+}
+
+// parseAmount amount of token in base units, amount with a decimal point like "12.5" is in whole tokens
+// and scaled by decimals of token, integer amount is already in base units
+func parseAmount(ctx contractapi.TransactionContextInterface, amount string, symbol string) (*big.Int, bool) {
+	if !strings.Contains(amount, ".") {
+		return new(big.Int).SetString(amount, 10)
+	}
+	decimals, err := getTokenDecimals(ctx, symbol)
+	if err != nil {
+		return nil, false
+	}
+	return parseAmountWithDecimals(amount, decimals)
+}
+
+// parseAmountWithDecimals amount in base units, fraction part must not be more precise than decimals
+func parseAmountWithDecimals(amount string, decimals uint64) (*big.Int, bool) {
+	if decimals > MAX_TOKEN_DECIMALS {
+		return nil, false
+	}
+	parts := strings.Split(amount, ".")
+	if len(parts) == 1 {
+		return new(big.Int).SetString(amount, 10)
+	}
+	if len(parts) != 2 || uint64(len(parts[1])) > decimals {
+		return nil, false
+	}
+	whole, fraction := parts[0], parts[1]
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+	if whole == "" || fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return nil, false
+	}
+	baseUnits, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10)
+	if !ok {
+		return nil, false
+	}
+	if negative {
+		baseUnits.Neg(baseUnits)
+	}
+	return baseUnits, true
+}
+
+// formatAmount amount in base units as whole tokens with trailing zeros removed, 12500000000000000000 with 18 decimals is "12.5"
+func formatAmount(amount *big.Int, decimals uint64) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-int(decimals)]
+	fraction := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	sign := ""
+	if amount.Sign() == -1 {
+		sign = "-"
+	}
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+func getTokenDecimals(ctx contractapi.TransactionContextInterface, symbol string) (uint64, error) {
+	if symbol == "" {
+		symbol = BUSY_COIN_SYMBOL
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	return token.Decimals, nil
+}
+
+// checkTokenNotPaused returns error if BUSY20 token is paused, BUSY can not be paused
+func checkTokenNotPaused(ctx contractapi.TransactionContextInterface, symbol string) error {
+	if strings.ToUpper(symbol) == BUSY_COIN_SYMBOL {
+		return nil
+	}
+	token, err := getBusy20Token(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if token.Paused {
+		return fmt.Errorf("token %s is paused", token.TokenSymbol)
+	}
+	return nil
+}
+
+// isTokenAdminOrNetworkAdmin check if invoker is busy network admin or owns tokenAdmin as default wallet
+func isTokenAdminOrNetworkAdmin(ctx contractapi.TransactionContextInterface, tokenAdmin string) (bool, error) {
+	mspid, _ := ctx.GetClientIdentity().GetMSPID()
+	commonName, _ := getCommonName(ctx)
+	if mspid == "BusyMSP" && commonName == "busy_network" {
+		return true, nil
+	}
+	defaultWalletAddress, err := getDefaultWalletAddress(ctx, commonName)
+	if err != nil {
+		return false, err
+	}
+	return tokenAdmin != "" && tokenAdmin == defaultWalletAddress, nil
+}
+
+// checkNewTokenOwner new owner of token has to be an existing wallet which is not frozen
+func checkNewTokenOwner(ctx contractapi.TransactionContextInterface, address string) error {
+	walletAsBytes, err := ctx.GetStub().GetState(address)
+	if err != nil {
+		return fmt.Errorf("error occurred while fetching wallet %s", err.Error())
+	}
+	if walletAsBytes == nil {
+		return fmt.Errorf("wallet %s does not exist", address)
+	}
+	var wallet Wallet
+	_ = json.Unmarshal(walletAsBytes, &wallet)
+	if wallet.DocType != "wallet" {
+		return fmt.Errorf("%s is not a wallet", address)
+	}
+	return checkWalletNotFrozen(ctx, address)
+}
+
+func sendPauseEvent(ctx contractapi.TransactionContextInterface, symbol string, paused bool) error {
+	pauseData := PauseEvent{
+		Symbol:        symbol,
+		Paused:        paused,
+		TransactionId: ctx.GetStub().GetTxID(),
+	}
+	pauseAsBytes, _ := json.Marshal(pauseData)
+	return ctx.GetStub().SetEvent(PAUSE_EVENT, pauseAsBytes)
+}
+
+func sendOwnershipEvent(ctx contractapi.TransactionContextInterface, symbol string, previousOwner string, newOwner string, pendingOwner string) error {
+	ownershipData := OwnershipEvent{
+		Symbol:        symbol,
+		PreviousOwner: previousOwner,
+		NewOwner:      newOwner,
+		PendingOwner:  pendingOwner,
+		TransactionId: ctx.GetStub().GetTxID(),
+	}
+	ownershipAsBytes, _ := json.Marshal(ownershipData)
+	return ctx.GetStub().SetEvent(OWNERSHIP_EVENT, ownershipAsBytes)
+}
+
 func generateTokenStateAddress(symbol string) string {
 	// symbol is case insensitive
 	symbol = strings.ToUpper(symbol)